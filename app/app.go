@@ -20,7 +20,14 @@ const (
 	TaskBarCharWid    float32 = TaskBarHeight/2 - TaskBarBorderSpan*2
 )
 
+//whether At() prints anything (off below log level "debug")
+var TraceCalls = true
+
 func At(path, s string) { //report the func location of currently running code
+	if !TraceCalls {
+		return
+	}
+
 	//centering assumes 80 columns
 
 	//center location
@@ -0,0 +1,125 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+//an amount of bytes, which can be written with a K/M/G/T suffix in config
+type ByteSize uint64
+
+func (b *ByteSize) UnmarshalYAML(unmarshal func(interface{}) error) error {
+	var s string
+	if err := unmarshal(&s); err != nil {
+		return err
+	}
+
+	n, err := ParseByteSize(s)
+	if err != nil {
+		return err
+	}
+
+	*b = n
+	return nil
+}
+
+func ParseByteSize(s string) (ByteSize, error) {
+	s = strings.ToUpper(strings.TrimSpace(s))
+	s = strings.TrimSuffix(strings.TrimSuffix(s, "B"), "I") //allow "2GB" & "2GiB"
+	multiplier := uint64(1)
+
+	if len(s) > 0 {
+		switch s[len(s)-1] {
+		case 'K':
+			multiplier = 1 << 10
+		case 'M':
+			multiplier = 1 << 20
+		case 'G':
+			multiplier = 1 << 30
+		case 'T':
+			multiplier = 1 << 40
+		}
+
+		if multiplier != 1 {
+			s = s[:len(s)-1]
+		}
+	}
+
+	n, err := strconv.ParseUint(strings.TrimSpace(s), 10, 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid size %q (e.g. 512M or 2G)", s)
+	}
+
+	return ByteSize(n * multiplier), nil
+}
+
+const DefaultAppCategory = "Apps"
+
+//names of the apps in each category (for the start menu), sorted
+func AppsByCategory() map[string][]string {
+	categories := map[string][]string{}
+
+	for name, a := range Global.Apps {
+		category := a.Category
+		if category == "" {
+			category = DefaultAppCategory
+		}
+
+		categories[category] = append(categories[category], name)
+	}
+
+	for _, names := range categories {
+		sort.Strings(names)
+	}
+
+	return categories
+}
+
+func (a *App) InheritsEnv() bool {
+	return a.InheritEnv == nil || *a.InheritEnv
+}
+
+//working directory with ${VAR}s expanded (empty means viscript's own)
+func (a *App) ExpandedCwd() string {
+	return os.ExpandEnv(a.Cwd)
+}
+
+//full environment for the app's process, nil meaning
+//"same as viscript's" (which is what exec.Cmd expects for that)
+func (a *App) BuildEnv() []string {
+	if len(a.Env) == 0 && a.InheritsEnv() {
+		return nil
+	}
+
+	env := []string{}
+
+	if a.InheritsEnv() {
+		env = append(env, os.Environ()...)
+	}
+
+	//sorted for predictable output.  when a key is also inherited,
+	//this later entry wins
+	keys := make([]string, 0, len(a.Env))
+
+	for k := range a.Env {
+		keys = append(keys, k)
+	}
+
+	sort.Strings(keys)
+
+	for _, k := range keys {
+		env = append(env, k+"="+os.ExpandEnv(a.Env[k]))
+	}
+
+	return env
+}
+
+func (l *Limits) IsSet() bool {
+	return l.MaxOpenFiles != 0 ||
+		l.MaxAddressSpace != 0 ||
+		l.MaxCPUSeconds != 0 ||
+		l.Nice != 0
+}
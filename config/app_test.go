@@ -0,0 +1,24 @@
+package config
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestAppsByCategory(t *testing.T) {
+	Global = Config{Apps: map[string]App{
+		"node":   {Category: "Meshnet"},
+		"client": {Category: "Meshnet"},
+		"editor": {},
+	}}
+
+	got := AppsByCategory()
+	want := map[string][]string{
+		"Meshnet":          {"client", "node"},
+		DefaultAppCategory: {"editor"},
+	}
+
+	if !reflect.DeepEqual(got, want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+}
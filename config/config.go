@@ -2,40 +2,36 @@ package config
 
 import (
 	"fmt"
-	"os"
-
-	"gopkg.in/yaml.v2"
+	"strings"
+	"time"
 )
 
 var Global Config
 
-const maxBufferSize = 32000
+const defaultStopGracePeriod = 5 * time.Second
 
+//makes the file the global config, unless it has errors (which are
+//all returned as Problems).  warnings are only printed
 func Load(configFileName string) error {
 	println("Loading configuration file:", configFileName)
 
-	var err error
-
-	file, err := os.Open(configFileName)
+	conf, problems, err := Parse(configFileName)
 	if err != nil {
 		return err
 	}
 
-	defer file.Close()
-
-	buffer := make([]byte, maxBufferSize)
-	n, err := file.Read(buffer)
-	if err != nil {
-		return err
+	if problems.HasErrors() {
+		return problems
 	}
 
-	Global = Config{}
-
-	err = yaml.Unmarshal(buffer[:n], &Global)
-	if err != nil {
-		return err
+	for _, p := range problems {
+		println(configFileName + ": " + p.String())
 	}
 
+	Global = conf
+	applyOverrides() //flags & environment win over the file
+	loaded(configFileName)
+
 	if Global.Settings.VerifyParsing {
 		fmt.Printf("[ Config ]\n")
 
@@ -45,6 +41,27 @@ func Load(configFileName string) error {
 			fmt.Printf("\tArgs: %v\n", app.Args)
 			fmt.Printf("\tDescription: %s\n\n", app.Desc)
 			fmt.Printf("\tHelp: %s\n\n", app.Help)
+
+			if app.Cwd != "" {
+				fmt.Printf("\tCwd: %s\n", app.Cwd)
+			}
+
+			if len(app.Env) > 0 || !app.InheritsEnv() {
+				fmt.Printf("\tEnv: %v (inherit: %t)\n", app.Env, app.InheritsEnv())
+			}
+
+			if app.Limits.IsSet() {
+				fmt.Printf("\tLimits: %+v\n", app.Limits)
+			}
+		}
+
+		for key, stack := range Global.Stacks {
+			fmt.Printf("[ Stack \"%s\" ]\n", key)
+
+			for _, sa := range stack.Apps {
+				fmt.Printf("\t%s (%s) %v depends on: %v ready: %+v\n",
+					sa.Name, sa.AppName(), sa.Args, sa.DependsOn, sa.Ready)
+			}
 		}
 
 		fmt.Printf("Default Settings:\n\n%+v\n\n", Global.Settings)
@@ -72,6 +89,43 @@ func GetPathWithDefaultArgsForApp(name string) []string {
 	return tokens
 }
 
+//the full command line for starting an app, with args as typed
+//after "start <app>" (checked against its params, if it has any)
+func GetTokensForApp(name string, args []string) ([]string, error) {
+	app := Global.Apps[name]
+
+	if len(app.Params) > 0 { //validate & fill in defaults
+		values, err := app.ResolveArgs(args)
+		if err != nil {
+			return nil, err
+		}
+
+		return append([]string{app.Path}, values...), nil
+	}
+
+	if len(args) == 0 {
+		return GetPathWithDefaultArgsForApp(name), nil
+	}
+
+	//if there are user passed args for the app override defaults set in config
+	tokens := []string{app.Path}
+
+	for _, arg := range args {
+		tokens = append(tokens, strings.ToLower(arg))
+	}
+
+	return tokens, nil
+}
+
 func DebugPrintInputEvents() bool {
 	return Global.Settings.VerboseInput
 }
+
+//how long an app gets to exit after SIGTERM, before it's sent SIGKILL
+func StopGracePeriod() time.Duration {
+	if Global.Settings.StopGracePeriod <= 0 {
+		return defaultStopGracePeriod
+	}
+
+	return Global.Settings.StopGracePeriod
+}
@@ -1,20 +1,46 @@
 package config
 
+import (
+	"time"
+)
+
 type App struct {
-	Daemon bool     `yaml:"daemon"`
-	Path   string   `yaml:"path"`
-	Args   []string `yaml:"default_args"`
-	Desc   string   `yaml:"desc"`
-	Help   string   `yaml:"help"`
+	Daemon     bool              `yaml:"daemon"`
+	Path       string            `yaml:"path"`
+	Args       []string          `yaml:"default_args"`
+	Desc       string            `yaml:"desc"`
+	Help       string            `yaml:"help"`
+	Category   string            `yaml:"category"`    //start menu submenu it's listed in (default: "Apps")
+	Params     []Param           `yaml:"params"`      //when given, default_args are not used
+	Cwd        string            `yaml:"cwd"`         //working directory (${VAR}s are expanded)
+	Env        map[string]string `yaml:"env"`         //extra environment (${VAR}s are expanded)
+	InheritEnv *bool             `yaml:"inherit_env"` //pass on viscript's environment (default: true)
+	Limits     Limits            `yaml:"limits"`
+}
+
+//applied to the app's process before it runs (0 means no limit)
+type Limits struct {
+	MaxOpenFiles    uint64   `yaml:"max_open_files"`
+	MaxAddressSpace ByteSize `yaml:"max_address_space"` //e.g. 2G
+	MaxCPUSeconds   uint64   `yaml:"max_cpu_seconds"`
+	Nice            int      `yaml:"nice"` //-20 (highest priority) to 19 (lowest)
 }
 
 type Settings struct {
-	VerboseInput  bool `yaml:"verboseInput"`
-	VerifyParsing bool `yaml:"verifyParsingByPrinting"`
-	RunHeadless   bool `yaml:"runHeadless"`
+	VerboseInput    bool          `yaml:"verboseInput"`
+	VerifyParsing   bool          `yaml:"verifyParsingByPrinting"`
+	RunHeadless     bool          `yaml:"runHeadless"`
+	StopGracePeriod time.Duration `yaml:"stopGracePeriod"`
+	RPCAddr         string        `yaml:"rpcAddr"`     //(see settings.go for defaults)
+	SignalAddr      string        `yaml:"signalAddr"`  //where apps' signal clients connect to
+	LogLevel        string        `yaml:"logLevel"`    //panic, fatal, error, warn, info or debug
+	WatchConfig     time.Duration `yaml:"watchConfig"` //how often to check the file for changes (0: never)
 }
 
 type Config struct {
-	Apps     map[string]App `yaml:"apps"`
-	Settings Settings       `yaml:"settings"`
+	Apps     map[string]App   `yaml:"apps"`
+	Stacks   map[string]Stack `yaml:"stacks"`
+	Settings Settings         `yaml:"settings"`
+	Theme    Theme            `yaml:"theme"`
+	Keys     Keybindings      `yaml:"keybindings"`
 }
@@ -0,0 +1,282 @@
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/skycoin/viscript/msg"
+)
+
+//actions keys can be bound to
+const (
+	ActionNewTerm   = "new_term"
+	ActionCloseTerm = "close_term" //the focused one
+	ActionFocusNext = "focus_next"
+	ActionFocusPrev = "focus_prev"
+	ActionDefocus   = "defocus"
+	ActionClear     = "clear"
+	ActionDetach    = "detach"    //the app attached to the focused terminal
+	ActionInterrupt = "interrupt" //^
+	ActionQuit      = "quit"      //close the viscript window
+	ActionCopy      = "copy"      //text selected by mouse, to the clipboard
+	ActionPaste     = "paste"     //from the clipboard, into the focused terminal
+	ActionNone      = "none"      //unbinds a default
+
+	//opens or closes it (see viewport/start_menu.go), for using it by keyboard
+	ActionStartMenu = "start_menu"
+
+	//layouts of the terminals (see viewport/terminal/layout.go)
+	ActionLayoutNext     = "layout_next"
+	ActionLayoutFloating = "layout_floating"
+	ActionLayoutGrid     = "layout_grid"
+	ActionLayoutMaster   = "layout_master"
+	ActionLayoutSplitH   = "layout_split_h"
+	ActionLayoutSplitV   = "layout_split_v"
+
+	//panes of the focused terminal's window
+	ActionSplitH   = "split_h" //new pane to the right
+	ActionSplitV   = "split_v" //new pane below
+	ActionPaneNext = "pane_next"
+	ActionPanePrev = "pane_prev"
+
+	//tabs of the focused terminal's window
+	ActionNewTab  = "new_tab"
+	ActionTabNext = "tab_next"
+	ActionTabPrev = "tab_prev"
+
+	//workspaces (see viewport/terminal/workspace.go).
+	//"workspace_<n>" shows the nth, counting from 1 in the taskbar switcher
+	ActionWorkspaceNext = "workspace_next"
+	ActionWorkspacePrev = "workspace_prev"
+	ActionWorkspaceN    = "workspace_"
+)
+
+var Actions = []string{
+	ActionNewTerm, ActionCloseTerm, ActionFocusNext, ActionFocusPrev, ActionDefocus,
+	ActionClear, ActionDetach, ActionInterrupt, ActionQuit, ActionCopy, ActionPaste, ActionStartMenu, ActionNone,
+	ActionLayoutNext, ActionLayoutFloating, ActionLayoutGrid, ActionLayoutMaster,
+	ActionLayoutSplitH, ActionLayoutSplitV,
+	ActionSplitH, ActionSplitV, ActionPaneNext, ActionPanePrev,
+	ActionNewTab, ActionTabNext, ActionTabPrev,
+	ActionWorkspaceNext, ActionWorkspacePrev} //(+ workspace_1 to 9, see init())
+
+const leaderPrefix = "leader "
+
+//the "keybindings:" section.  chords look like "ctrl+shift+t", & ones
+//starting with "leader " (e.g. "leader c") only work right after the
+//leader chord was pressed, like in tmux
+type Keybindings struct {
+	Leader   string            `yaml:"leader"`   //e.g. "ctrl+b" (none by default)
+	Bindings map[string]string `yaml:"bindings"` //chord to action, on top of DefaultBindings
+}
+
+var DefaultBindings = map[string]string{
+	"ctrl+c":           ActionInterrupt,
+	"ctrl+z":           ActionDetach,
+	"ctrl+l":           ActionClear,
+	"ctrl+shift+t":     ActionNewTerm,
+	"ctrl+shift+w":     ActionCloseTerm,
+	"ctrl+shift+c":     ActionCopy,
+	"ctrl+shift+v":     ActionPaste,
+	"ctrl+tab":         ActionFocusNext,
+	"ctrl+shift+tab":   ActionFocusPrev,
+	"ctrl+shift+space": ActionLayoutNext,
+	"ctrl+shift+e":     ActionSplitH,
+	"ctrl+shift+o":     ActionSplitV,
+	"ctrl+shift+n":     ActionPaneNext,
+	"ctrl+shift+p":     ActionPanePrev,
+	"ctrl+shift+y":     ActionNewTab,
+	"ctrl+page_down":   ActionTabNext,
+	"ctrl+page_up":     ActionTabPrev,
+	"ctrl+alt+right":   ActionWorkspaceNext,
+	"ctrl+alt+left":    ActionWorkspacePrev,
+	"ctrl+escape":      ActionStartMenu,
+
+	//(only when there's a leader)
+	"leader space":     ActionLayoutNext,
+	"leader c":         ActionNewTerm,
+	"leader x":         ActionCloseTerm,
+	"leader n":         ActionFocusNext,
+	"leader p":         ActionFocusPrev,
+	"leader d":         ActionDetach,
+	"leader backslash": ActionSplitH,
+	"leader minus":     ActionSplitV,
+	"leader o":         ActionPaneNext,
+	"leader semicolon": ActionPanePrev,
+	"leader t":         ActionNewTab,
+	"leader period":    ActionTabNext,
+	"leader comma":     ActionTabPrev,
+	"leader right":     ActionWorkspaceNext,
+	"leader left":      ActionWorkspacePrev,
+
+	//(like tmux)
+	"leader left_bracket":  ActionCopy,
+	"leader right_bracket": ActionPaste,
+
+	//(& "leader 1" to 9 for workspace_1 to 9, see init())
+}
+
+func init() {
+	for n := 1; n <= 9; n++ {
+		action := ActionWorkspaceN + strconv.Itoa(n)
+		Actions = append(Actions, action)
+		DefaultBindings["leader "+strconv.Itoa(n)] = action
+	}
+}
+
+type Chord struct {
+	Key uint32 //msg.Key*
+	Mod uint8  //msg.GLFW_MOD_* bits
+}
+
+//Keybindings, parsed & merged with the defaults
+type KeyMap struct {
+	Leader      *Chord //nil when there's none
+	Direct      map[Chord]string
+	AfterLeader map[Chord]string
+}
+
+var modNames = map[string]uint8{
+	"shift":   msg.GLFW_MOD_SHIFT,
+	"ctrl":    msg.GLFW_MOD_CONTROL,
+	"control": msg.GLFW_MOD_CONTROL,
+	"alt":     msg.GLFW_MOD_ALT,
+	"super":   msg.GLFW_MOD_SUPER,
+}
+
+var keyNames = map[string]uint32{
+	"space": msg.KeySpace, "apostrophe": msg.KeyApostrophe, "comma": msg.KeyComma,
+	"minus": msg.KeyMinus, "period": msg.KeyPeriod, "slash": msg.KeySlash,
+	"semicolon": msg.KeySemicolon, "equal": msg.KeyEqual, "backslash": msg.KeyBackslash,
+	"left_bracket": msg.KeyLeftBracket, "right_bracket": msg.KeyRightBracket,
+	"grave": msg.KeyGraveAccent, "escape": msg.KeyEscape, "esc": msg.KeyEscape,
+	"enter": msg.KeyEnter, "tab": msg.KeyTab, "backspace": msg.KeyBackspace,
+	"insert": msg.KeyInsert, "delete": msg.KeyDelete,
+	"right": msg.KeyRight, "left": msg.KeyLeft, "down": msg.KeyDown, "up": msg.KeyUp,
+	"page_up": msg.KeyPageUp, "page_down": msg.KeyPageDown,
+	"home": msg.KeyHome, "end": msg.KeyEnd, "pause": msg.KeyPause, "menu": msg.KeyMenu,
+}
+
+//e.g. "ctrl+shift+t", "alt+f4" or "escape"
+func ParseChord(s string) (Chord, error) {
+	parts := strings.Split(strings.ToLower(strings.TrimSpace(s)), "+")
+	c := Chord{}
+
+	for _, mod := range parts[:len(parts)-1] {
+		bit, ok := modNames[mod]
+		if !ok {
+			return c, fmt.Errorf("\"%s\" isn't a modifier (use ctrl, shift, alt or super)", mod)
+		}
+
+		c.Mod |= bit
+	}
+
+	key := parts[len(parts)-1]
+
+	switch {
+	case len(key) == 1 && key[0] >= 'a' && key[0] <= 'z':
+		c.Key = msg.KeyA + uint32(key[0]-'a')
+	case len(key) == 1 && key[0] >= '0' && key[0] <= '9':
+		c.Key = msg.Key0 + uint32(key[0]-'0')
+	case len(key) > 1 && key[0] == 'f':
+		n, err := strconv.Atoi(key[1:])
+		if err != nil || n < 1 || n > 25 {
+			return c, fmt.Errorf("unknown key \"%s\"", key)
+		}
+
+		c.Key = msg.KeyF1 + uint32(n-1)
+	default:
+		code, ok := keyNames[key]
+		if !ok {
+			return c, fmt.Errorf("unknown key \"%s\"", key)
+		}
+
+		c.Key = code
+	}
+
+	return c, nil
+}
+
+func (k Keybindings) Resolve() (KeyMap, error) {
+	var first error
+
+	km := k.resolve(func(_ string, err error) {
+		if first == nil {
+			first = err
+		}
+	})
+
+	return km, first
+}
+
+//
+//
+//private
+//
+//
+
+//reports problems by the yaml key they're under ("leader" or a chord)
+func (k Keybindings) resolve(report func(key string, err error)) KeyMap {
+	km := KeyMap{
+		Direct:      make(map[Chord]string),
+		AfterLeader: make(map[Chord]string)}
+
+	if k.Leader != "" {
+		leader, err := ParseChord(k.Leader)
+		if err != nil {
+			report("leader", fmt.Errorf("leader: %v", err))
+		} else {
+			km.Leader = &leader
+		}
+	}
+
+	//defaults 1st, so configured ones replace them
+	for _, bindings := range []map[string]string{DefaultBindings, k.Bindings} {
+		chords := make([]string, 0, len(bindings))
+
+		for chord := range bindings {
+			chords = append(chords, chord)
+		}
+
+		sort.Strings(chords)
+
+		for _, chord := range chords {
+			action := strings.ToLower(strings.TrimSpace(bindings[chord]))
+			if !contains(Actions, action) {
+				report(chord, fmt.Errorf("\"%s\": unknown action \"%s\" (use %s)",
+					chord, action, strings.Join(Actions, ", ")))
+				continue
+			}
+
+			keys := strings.TrimSpace(chord)
+			target := km.Direct
+
+			if strings.HasPrefix(keys, leaderPrefix) {
+				keys = strings.TrimSpace(keys[len(leaderPrefix):])
+				target = km.AfterLeader
+			}
+
+			c, err := ParseChord(keys)
+			if err != nil {
+				report(chord, fmt.Errorf("\"%s\": %v", chord, err))
+				continue
+			}
+
+			if action == ActionNone {
+				delete(target, c)
+			} else {
+				target[c] = action
+			}
+		}
+	}
+
+	for chord := range k.Bindings {
+		if k.Leader == "" && strings.HasPrefix(strings.TrimSpace(chord), leaderPrefix) {
+			report(chord, fmt.Errorf("\"%s\" needs a leader, but none is set", chord))
+		}
+	}
+
+	return km
+}
@@ -0,0 +1,79 @@
+package config
+
+import (
+	"testing"
+
+	"github.com/skycoin/viscript/msg"
+)
+
+func TestParseChord(t *testing.T) {
+	tests := map[string]Chord{
+		"escape":         {msg.KeyEscape, 0},
+		"Ctrl+Shift+T":   {msg.KeyT, msg.GLFW_MOD_CONTROL | msg.GLFW_MOD_SHIFT},
+		"alt+f4":         {msg.KeyF4, msg.GLFW_MOD_ALT},
+		"super+7":        {msg.Key7, msg.GLFW_MOD_SUPER},
+		"ctrl+page_down": {msg.KeyPageDown, msg.GLFW_MOD_CONTROL},
+	}
+
+	for s, want := range tests {
+		if c, err := ParseChord(s); err != nil || c != want {
+			t.Fatalf("%s: got %v, %v", s, c, err)
+		}
+	}
+
+	for _, s := range []string{"", "ctrl+", "hyper+a", "f26", "ctrl+shift"} {
+		if _, err := ParseChord(s); err == nil {
+			t.Fatalf("%s: expected an error", s)
+		}
+	}
+}
+
+func TestKeybindingsResolve(t *testing.T) {
+	k := Keybindings{
+		Leader: "ctrl+b",
+		Bindings: map[string]string{
+			"escape":   ActionQuit,
+			"ctrl+l":   ActionNone,
+			"leader c": ActionClear,
+		}}
+
+	km, err := k.Resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if *km.Leader != (Chord{msg.KeyB, msg.GLFW_MOD_CONTROL}) {
+		t.Fatal("wrong leader:", *km.Leader)
+	}
+
+	if km.Direct[Chord{msg.KeyEscape, 0}] != ActionQuit {
+		t.Fatal("escape wasn't bound")
+	}
+
+	if _, bound := km.Direct[Chord{msg.KeyL, msg.GLFW_MOD_CONTROL}]; bound {
+		t.Fatal("ctrl+l wasn't unbound")
+	}
+
+	if km.AfterLeader[Chord{msg.KeyC, 0}] != ActionClear ||
+		km.AfterLeader[Chord{msg.KeyX, 0}] != ActionCloseTerm {
+		t.Fatal("wrong leader bindings:", km.AfterLeader)
+	}
+
+	if km.AfterLeader[Chord{msg.Key3, 0}] != ActionWorkspaceN+"3" {
+		t.Fatal("workspace_3 wasn't bound to leader 3")
+	}
+
+	if _, err := (Keybindings{}).Resolve(); err != nil {
+		t.Fatal("defaults:", err)
+	}
+
+	for _, k := range []Keybindings{
+		{Leader: "ctrl+bee"},
+		{Bindings: map[string]string{"ctrl+q": "explode"}},
+		{Bindings: map[string]string{"leader q": ActionQuit}}, //no leader
+	} {
+		if _, err := k.Resolve(); err == nil {
+			t.Fatalf("%+v: expected an error", k)
+		}
+	}
+}
@@ -0,0 +1,136 @@
+package config
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+)
+
+//line numbers (1 based) of the keys & list items in a yaml file, by
+//path, e.g. "apps.meshnet-node.path" or "stacks.meshnet.apps.0.name".
+//yaml.v2 doesn't keep track of where values came from, so this is
+//found separately.  it only understands block style, which is all
+//that matters for pointing at problems
+type lineIndex map[string]int
+
+var keyPattern = regexp.MustCompile(`^("[^"]*"|'[^']*'|[^\s"'#:][^:#]*?)\s*:(\s|$)`)
+
+type indexLevel struct {
+	indent int
+	name   string
+	isItem bool //a "- " list item, named by its index
+}
+
+func indexLines(data []byte) lineIndex {
+	li := make(lineIndex)
+	itemCounts := make(map[string]int) //by path of the list
+	stack := []indexLevel{}
+	blockIndent := -1 //indent of a key with a "|" or ">" value, whose lines we're in
+
+	pathOf := func(name string) string {
+		parts := make([]string, 0, len(stack)+1)
+
+		for _, level := range stack {
+			parts = append(parts, level.name)
+		}
+
+		return strings.Join(append(parts, name), ".")
+	}
+
+	for i, line := range strings.Split(string(data), "\n") {
+		content := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " "))
+
+		if blockIndent >= 0 {
+			if content == "" || indent > blockIndent {
+				continue
+			}
+
+			blockIndent = -1
+		}
+
+		if content == "" || content[0] == '#' || content == "---" {
+			continue
+		}
+
+		//list item, which may start a mapping right away ("- name: x")
+		if content == "-" || strings.HasPrefix(content, "- ") {
+			for len(stack) > 0 {
+				top := stack[len(stack)-1]
+				if top.indent < indent || (top.indent == indent && !top.isItem) {
+					break
+				}
+
+				stack = stack[:len(stack)-1]
+			}
+
+			list := pathOf("")
+			list = strings.TrimSuffix(list, ".")
+			index := strconv.Itoa(itemCounts[list])
+			itemCounts[list]++
+
+			li[pathOf(index)] = i + 1
+			stack = append(stack, indexLevel{indent, index, true})
+
+			rest := strings.TrimLeft(content[1:], " ")
+			indent += len(content) - len(rest)
+			content = rest
+
+			if content == "" {
+				continue
+			}
+		}
+
+		m := keyPattern.FindStringSubmatch(content)
+		if m == nil { //a plain value
+			continue
+		}
+
+		for len(stack) > 0 && stack[len(stack)-1].indent >= indent {
+			stack = stack[:len(stack)-1]
+		}
+
+		name := strings.Trim(m[1], `"'`)
+		li[pathOf(name)] = i + 1
+
+		value := strings.TrimSpace(content[len(m[0]):])
+		if strings.HasPrefix(value, "|") || strings.HasPrefix(value, ">") {
+			blockIndent = indent
+			continue
+		}
+
+		if value == "" || value[0] == '#' { //nested mapping or list follows
+			stack = append(stack, indexLevel{indent, name, false})
+		}
+	}
+
+	return li
+}
+
+//line of the path, or of its closest parent that's in the file (0 if none)
+func (li lineIndex) lineOf(path ...string) int {
+	for n := len(path); n > 0; n-- {
+		if line, ok := li[strings.Join(path[:n], ".")]; ok {
+			return line
+		}
+	}
+
+	return 0
+}
+
+//first line at/after 'from' with a key called name
+func (li lineIndex) keyLineAfter(from int, name string) int {
+	best := 0
+
+	for path, line := range li {
+		if line < from || (best != 0 && line >= best) {
+			continue
+		}
+
+		if path == name || strings.HasSuffix(path, "."+name) {
+			best = line
+		}
+	}
+
+	return best
+}
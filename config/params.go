@@ -0,0 +1,244 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"strconv"
+	"strings"
+)
+
+const (
+	ParamTypeHostPort = "host:port"
+	ParamTypeBool     = "bool"
+	ParamTypeInt      = "int"
+	ParamTypeString   = "string"
+
+	//replaced by a currently unused TCP port when the app starts
+	FreePortPlaceholder = "{free_port}"
+)
+
+//one (positional) command line argument of an app
+type Param struct {
+	Name     string `yaml:"name"`
+	Type     string `yaml:"type"` //one of the ParamType___ values (default: string)
+	Required bool   `yaml:"required"`
+	Default  string `yaml:"default"`
+	Desc     string `yaml:"desc"`
+}
+
+func (p *Param) TypeOrDefault() string {
+	if p.Type == "" {
+		return ParamTypeString
+	}
+
+	return p.Type
+}
+
+//returns the value as it should be passed to the app
+func (p *Param) Check(value string) (string, error) {
+	switch p.TypeOrDefault() {
+
+	case ParamTypeHostPort:
+		_, port, err := net.SplitHostPort(value)
+		if err != nil {
+			return "", fmt.Errorf("%s: \"%s\" is not host:port", p.Name, value)
+		}
+
+		if _, err = parsePort(port); err != nil {
+			return "", fmt.Errorf("%s: %v", p.Name, err)
+		}
+
+	case ParamTypeBool:
+		b, err := parseBool(value)
+		if err != nil {
+			return "", fmt.Errorf("%s: \"%s\" is not true/false", p.Name, value)
+		}
+
+		value = strconv.FormatBool(b)
+
+	case ParamTypeInt:
+		if _, err := strconv.Atoi(value); err != nil {
+			return "", fmt.Errorf("%s: \"%s\" is not a whole number", p.Name, value)
+		}
+
+	case ParamTypeString:
+
+	default:
+		return "", fmt.Errorf("%s: unknown type \"%s\"", p.Name, p.Type)
+	}
+
+	return value, nil
+}
+
+//turns what the user typed after "start <app>" into the app's arguments.
+//args can be positional ("8000") or named ("port=8000"), and be mixed
+func (a *App) ResolveArgs(args []string) ([]string, error) {
+	values := make([]string, len(a.Params))
+	given := make([]bool, len(a.Params))
+	next := 0 //next positional param
+
+	for _, arg := range args {
+		if arg == "" { //from repeated spaces
+			continue
+		}
+
+		if i, value, ok := a.namedArg(arg); ok {
+			values[i] = value
+			given[i] = true
+			continue
+		}
+
+		for next < len(a.Params) && given[next] {
+			next++
+		}
+
+		if next >= len(a.Params) {
+			return nil, fmt.Errorf("too many arguments (expected %d)", len(a.Params))
+		}
+
+		values[next] = arg
+		given[next] = true
+	}
+
+	//fill in defaults & check everything.  trailing params which
+	//are neither given nor have a default are simply left out
+	last := -1
+
+	for i := range a.Params {
+		p := &a.Params[i]
+
+		if !given[i] {
+			if p.Default == "" {
+				if p.Required {
+					return nil, fmt.Errorf("%s is required", p.Name)
+				}
+
+				continue
+			}
+
+			values[i] = p.Default
+		}
+
+		if strings.Contains(values[i], FreePortPlaceholder) {
+			port, err := FreeTCPPort()
+			if err != nil {
+				return nil, err
+			}
+
+			values[i] = strings.Replace(values[i], FreePortPlaceholder, strconv.Itoa(port), -1)
+		}
+
+		value, err := p.Check(values[i])
+		if err != nil {
+			return nil, err
+		}
+
+		values[i] = value
+		last = i
+	}
+
+	//positional args can't have holes
+	for i := 0; i < last; i++ {
+		if values[i] == "" {
+			return nil, fmt.Errorf("%s must be given, because %s is",
+				a.Params[i].Name, a.Params[last].Name)
+		}
+	}
+
+	return values[:last+1], nil
+}
+
+//generated "help <app>" text
+func (a *App) Usage(appName string) string {
+	if len(a.Params) == 0 {
+		return ""
+	}
+
+	s := "Usage: start " + appName
+	longest := 0
+
+	for _, p := range a.Params {
+		if p.Required {
+			s += " <" + p.Name + ">"
+		} else {
+			s += " [" + p.Name + "]"
+		}
+
+		if len(p.Name) > longest {
+			longest = len(p.Name)
+		}
+	}
+
+	s += "\n"
+
+	for i, p := range a.Params {
+		s += fmt.Sprintf("[%d] %-*s  %-9s ", i+1, longest, p.Name, p.TypeOrDefault())
+
+		switch {
+		case p.Required:
+			s += "(required) "
+		case p.Default != "":
+			s += "(default: " + p.Default + ") "
+		}
+
+		s += p.Desc + "\n"
+	}
+
+	return s + "Args can also be given by name, e.g. " +
+		a.Params[len(a.Params)-1].Name + "=..."
+}
+
+//asks the OS for a TCP port that's free right now.  (there's no
+//guarantee it still is when the app gets around to listening on it)
+func FreeTCPPort() (int, error) {
+	l, err := net.Listen("tcp", ":0")
+	if err != nil {
+		return 0, errors.New("Couldn't find a free TCP port: " + err.Error())
+	}
+
+	defer l.Close()
+	return l.Addr().(*net.TCPAddr).Port, nil
+}
+
+//
+//
+//private
+//
+//
+
+func (a *App) namedArg(arg string) (index int, value string, ok bool) {
+	eq := strings.IndexByte(arg, '=')
+	if eq < 1 {
+		return 0, "", false
+	}
+
+	for i, p := range a.Params {
+		if strings.EqualFold(p.Name, arg[:eq]) {
+			return i, arg[eq+1:], true
+		}
+	}
+
+	return 0, "", false
+}
+
+//like strconv.ParseBool, plus yes/no & on/off
+func parseBool(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "yes", "on":
+		return true, nil
+	case "no", "off":
+		return false, nil
+	}
+
+	return strconv.ParseBool(s)
+}
+
+func parsePort(s string) (int, error) {
+	port, err := strconv.Atoi(s)
+	if err != nil || port < 0 || port > 65535 {
+		return 0, fmt.Errorf("\"%s\" is not a valid port", s)
+	}
+
+	return port, nil
+}
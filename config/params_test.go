@@ -0,0 +1,61 @@
+package config
+
+import (
+	"reflect"
+	"strconv"
+	"testing"
+)
+
+var testApp = App{Params: []Param{
+	{Name: "control-addr", Type: ParamTypeHostPort, Required: true},
+	{Name: "random", Type: ParamTypeBool, Default: "false"},
+	{Name: "port", Type: ParamTypeInt, Default: FreePortPlaceholder},
+	{Name: "alias"},
+}}
+
+func TestResolveArgs(t *testing.T) {
+	tests := []struct {
+		args []string
+		want []string
+	}{
+		{[]string{"1.2.3.4:5000", "1", "8000"}, []string{"1.2.3.4:5000", "true", "8000"}},
+		{[]string{"alias=node0", "1.2.3.4:5000", "port=8000"}, []string{"1.2.3.4:5000", "false", "8000", "node0"}},
+		{[]string{"1.2.3.4:5000", "", "no", "80", "x=y"}, []string{"1.2.3.4:5000", "false", "80", "x=y"}},
+		{[]string{"1.2.3.4:5000"}, []string{"1.2.3.4:5000", "false", ""}},
+	}
+
+	for _, test := range tests {
+		got, err := testApp.ResolveArgs(test.args)
+		if err != nil {
+			t.Fatalf("%v: %v", test.args, err)
+		}
+
+		if test.want[2] == "" { //free port
+			if _, err := strconv.Atoi(got[2]); err != nil {
+				t.Fatalf("%v: no port allocated: %v", test.args, got)
+			}
+
+			test.want[2] = got[2]
+		}
+
+		if !reflect.DeepEqual(got, test.want) {
+			t.Fatalf("%v: got %v, want %v", test.args, got, test.want)
+		}
+	}
+}
+
+func TestResolveArgsErrors(t *testing.T) {
+	bad := [][]string{
+		{},                                       //missing required
+		{"1.2.3.4"},                              //no port
+		{"1.2.3.4:5000", "maybe"},                //not a bool
+		{"1.2.3.4:5000", "true", "eighty"},       //not an int
+		{"1.2.3.4:5000", "true", "80", "a", "b"}, //too many
+	}
+
+	for _, args := range bad {
+		if _, err := testApp.ResolveArgs(args); err == nil {
+			t.Fatalf("%v: expected an error", args)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package config
+
+/*
+	Reloading swaps config.Global for a freshly loaded one.  It's only
+	done from the main loop (like everything else touching Global), so
+	there's no locking.  Running apps keep the config they started with.
+*/
+
+import (
+	"errors"
+	"os"
+	"reflect"
+	"sort"
+	"time"
+)
+
+var (
+	loadedPath    string
+	generation    int       //+1 per successful Load(), so users can tell it changed
+	lastCheck     time.Time //when the file's mtime was last looked at
+	lastSeenMTime time.Time //of the file that was loaded, or failed loading
+)
+
+//what changed between 2 configs
+type Diff struct {
+	Added   []string //app names
+	Removed []string
+	Changed []string
+
+	NeedsRestart []string //settings that only apply when viscript starts
+}
+
+func (d *Diff) IsEmpty() bool {
+	return len(d.Added) == 0 && len(d.Removed) == 0 &&
+		len(d.Changed) == 0 && len(d.NeedsRestart) == 0
+}
+
+//one line per change, e.g. "+ meshnet-node"
+func (d *Diff) Lines() []string {
+	lines := []string{}
+
+	for _, name := range d.Added {
+		lines = append(lines, "+ "+name)
+	}
+
+	for _, name := range d.Removed {
+		lines = append(lines, "- "+name)
+	}
+
+	for _, name := range d.Changed {
+		lines = append(lines, "~ "+name)
+	}
+
+	for _, name := range d.NeedsRestart {
+		lines = append(lines, "! "+name+" changed, but only takes effect after a restart")
+	}
+
+	return lines
+}
+
+//path of the currently loaded config file
+func Path() string {
+	return loadedPath
+}
+
+func Generation() int {
+	return generation
+}
+
+//loads the current config file again.  when it's invalid,
+//the current config stays as it is
+func Reload() (Diff, error) {
+	if loadedPath == "" {
+		return Diff{}, errors.New("No config file was loaded, so there's nothing to reload")
+	}
+
+	old := Global
+	lastSeenMTime = modTimeOf(loadedPath)
+
+	err := Load(loadedPath)
+	if err != nil {
+		return Diff{}, err
+	}
+
+	diff := diffApps(old.Apps, Global.Apps)
+	keepStartupSettings(&old.Settings, &diff)
+	return diff, nil
+}
+
+//polls the config file's mtime (when "watchConfig" is set, at that
+//interval) & reloads it when it changed.  err is set when it changed
+//but couldn't be loaded (reported once per change)
+func ReloadIfChanged() (diff Diff, reloaded bool, err error) {
+	interval := Global.Settings.WatchConfig
+
+	if interval <= 0 || loadedPath == "" || time.Since(lastCheck) < interval {
+		return
+	}
+
+	lastCheck = time.Now()
+
+	mtime := modTimeOf(loadedPath)
+	if mtime.IsZero() || mtime.Equal(lastSeenMTime) {
+		return
+	}
+
+	diff, err = Reload()
+	return diff, err == nil, err
+}
+
+//
+//
+//private
+//
+//
+
+//called by Load() after it replaced Global
+func loaded(path string) {
+	loadedPath = path
+	lastSeenMTime = modTimeOf(path)
+	generation++
+}
+
+func modTimeOf(path string) time.Time {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}
+	}
+
+	return info.ModTime()
+}
+
+func diffApps(old, new map[string]App) (d Diff) {
+	for name, app := range new {
+		prev, existed := old[name]
+
+		if !existed {
+			d.Added = append(d.Added, name)
+		} else if !reflect.DeepEqual(prev, app) {
+			d.Changed = append(d.Changed, name)
+		}
+	}
+
+	for name := range old {
+		if _, exists := new[name]; !exists {
+			d.Removed = append(d.Removed, name)
+		}
+	}
+
+	sort.Strings(d.Added)
+	sort.Strings(d.Removed)
+	sort.Strings(d.Changed)
+	return d
+}
+
+//the main loop, RPC & signal servers were set up with these, so
+//changing them now would only make Global lie about what's running
+func keepStartupSettings(old *Settings, d *Diff) {
+	s := &Global.Settings
+
+	if s.RunHeadless != old.RunHeadless {
+		d.NeedsRestart = append(d.NeedsRestart, "runHeadless")
+		s.RunHeadless = old.RunHeadless
+	}
+
+	if RPCAddr() != valueOrDefault(old.RPCAddr, DefaultRPCAddr) {
+		d.NeedsRestart = append(d.NeedsRestart, "rpcAddr")
+		s.RPCAddr = old.RPCAddr
+	}
+
+	if SignalAddr() != valueOrDefault(old.SignalAddr, DefaultSignalAddr) {
+		d.NeedsRestart = append(d.NeedsRestart, "signalAddr")
+		s.SignalAddr = old.SignalAddr
+	}
+
+	if LogLevel() != valueOrDefault(old.LogLevel, DefaultLogLevel) {
+		d.NeedsRestart = append(d.NeedsRestart, "logLevel")
+		s.LogLevel = old.LogLevel
+	}
+}
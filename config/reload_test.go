@@ -0,0 +1,100 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestDiffApps(t *testing.T) {
+	old := map[string]App{"a": {Path: "/bin/a"}, "b": {Path: "/bin/b"}, "c": {Path: "/bin/c"}}
+	new := map[string]App{"b": {Path: "/bin/b2"}, "c": {Path: "/bin/c"}, "d": {Path: "/bin/d"}}
+
+	d := diffApps(old, new)
+	want := Diff{Added: []string{"d"}, Removed: []string{"a"}, Changed: []string{"b"}}
+
+	if !reflect.DeepEqual(d, want) {
+		t.Fatalf("got %+v", d)
+	}
+
+	if d := diffApps(old, old); !d.IsEmpty() {
+		t.Fatalf("expected no changes, got %+v", d)
+	}
+}
+
+func TestReload(t *testing.T) {
+	os.Unsetenv(EnvRPCAddr)
+	SetOverrides(Overrides{})
+
+	defer func() {
+		Global = Config{}
+		loadedPath = ""
+	}()
+
+	f, err := ioutil.TempFile("", "viscript-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+	f.Close()
+
+	//(the mtime is set by hand, so each write counts as a change)
+	mtime := time.Now().Add(-time.Hour)
+	write := func(s string) {
+		ioutil.WriteFile(f.Name(), []byte(s), 0644)
+		mtime = mtime.Add(time.Second)
+		os.Chtimes(f.Name(), mtime, mtime)
+	}
+
+	write("apps:\n  sh:\n    path: /bin/sh\n  true:\n    path: /bin/true\n" +
+		"settings:\n  rpcAddr: \":1111\"\n  watchConfig: 1ms\n")
+
+	if err := Load(f.Name()); err != nil {
+		t.Fatal(err)
+	}
+
+	generation := Generation()
+
+	//an app added, one removed & one changed, & a startup-only setting
+	write("apps:\n  sh:\n    path: /bin/sh\n    default_args: [\"-i\"]\n  false:\n    path: /bin/false\n" +
+		"settings:\n  rpcAddr: \":2222\"\n  watchConfig: 1ms\n")
+
+	diff, err := Reload()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := Diff{Added: []string{"false"}, Removed: []string{"true"},
+		Changed: []string{"sh"}, NeedsRestart: []string{"rpcAddr"}}
+
+	if !reflect.DeepEqual(diff, want) {
+		t.Fatalf("got %+v", diff)
+	}
+
+	if RPCAddr() != ":1111" || Generation() != generation+1 {
+		t.Fatalf("rpcAddr should stay until a restart, got %s (generation %d)",
+			RPCAddr(), Generation())
+	}
+
+	//an invalid one leaves Global as it was, & is reported once
+	before := Global
+	write("apps:\n  sh:\n    colour: red\n")
+	time.Sleep(2 * time.Millisecond)
+
+	if _, reloaded, err := ReloadIfChanged(); reloaded || err == nil {
+		t.Fatal("expected the invalid config to be reported")
+	}
+
+	if !reflect.DeepEqual(Global, before) || Generation() != generation+1 {
+		t.Fatalf("an invalid config changed Global: %+v", Global)
+	}
+
+	time.Sleep(2 * time.Millisecond)
+
+	if _, reloaded, err := ReloadIfChanged(); reloaded || err != nil {
+		t.Fatalf("expected nothing, as the file didn't change again, got %v", err)
+	}
+}
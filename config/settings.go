@@ -0,0 +1,186 @@
+package config
+
+/*
+	Where each setting comes from, highest priority first:
+
+	1. command line flags (see SetOverrides)
+	2. environment variables (VISCRIPT_*)
+	3. the "settings:" section of the config file
+	4. the defaults below
+
+	Flags & environment are re-applied whenever a file is loaded.
+*/
+
+import (
+	"errors"
+	"net"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	DefaultRPCAddr    = ":7777"
+	DefaultSignalAddr = "0.0.0.0:7999"
+	DefaultLogLevel   = "debug" //(debug also prints every app.At() trace)
+
+	fileName   = "config.yaml"
+	appDirName = "viscript"
+)
+
+//environment variables
+const (
+	EnvConfig     = "VISCRIPT_CONFIG"
+	EnvHeadless   = "VISCRIPT_HEADLESS"
+	EnvRPCAddr    = "VISCRIPT_RPC_ADDR"
+	EnvSignalAddr = "VISCRIPT_SIGNAL_ADDR"
+	EnvLogLevel   = "VISCRIPT_LOG_LEVEL"
+
+	envLegacyRPCPort = "TERMINAL_RPC_PORT" //only a port. VISCRIPT_RPC_ADDR wins
+	envSignalServer  = "SIGNAL_SERVER_ADDRESS"
+)
+
+//settings given on the command line.  empty (or nil) means not given
+type Overrides struct {
+	Headless   *bool
+	RPCAddr    string
+	SignalAddr string
+	LogLevel   string
+}
+
+var overrides Overrides
+
+func SetOverrides(o Overrides) {
+	overrides = o
+	applyOverrides()
+}
+
+//where the config file is looked for, in order, when it's not given
+//by -config or VISCRIPT_CONFIG.  the current directory comes first,
+//as that's where viscript always used to look
+func SearchPaths() []string {
+	paths := []string{fileName}
+
+	configHome := os.Getenv("XDG_CONFIG_HOME")
+	if configHome == "" {
+		if home := os.Getenv("HOME"); home != "" {
+			configHome = filepath.Join(home, ".config")
+		}
+	}
+
+	if configHome != "" {
+		paths = append(paths, filepath.Join(configHome, appDirName, fileName))
+	}
+
+	configDirs := os.Getenv("XDG_CONFIG_DIRS")
+	if configDirs == "" {
+		configDirs = "/etc/xdg"
+	}
+
+	for _, dir := range filepath.SplitList(configDirs) {
+		if dir != "" {
+			paths = append(paths, filepath.Join(dir, appDirName, fileName))
+		}
+	}
+
+	return paths
+}
+
+func FindFile() (string, error) {
+	if path := os.Getenv(EnvConfig); path != "" {
+		return path, nil
+	}
+
+	paths := SearchPaths()
+
+	for _, path := range paths {
+		if info, err := os.Stat(path); err == nil && !info.IsDir() {
+			return path, nil
+		}
+	}
+
+	return "", errors.New("No config file found (looked for " +
+		strings.Join(paths, ", ") + ")")
+}
+
+func RPCAddr() string {
+	return valueOrDefault(Global.Settings.RPCAddr, DefaultRPCAddr)
+}
+
+func SignalAddr() string {
+	return valueOrDefault(Global.Settings.SignalAddr, DefaultSignalAddr)
+}
+
+//what apps should connect to, which differs from SignalAddr()
+//when listening on all interfaces (e.g. 0.0.0.0:7999)
+func SignalClientAddr() string {
+	host, port, err := net.SplitHostPort(SignalAddr())
+	if err != nil {
+		return SignalAddr()
+	}
+
+	if ip := net.ParseIP(host); host == "" || (ip != nil && ip.IsUnspecified()) {
+		host = "localhost"
+	}
+
+	return net.JoinHostPort(host, port)
+}
+
+//passed to every app, so it finds the signal server wherever it is
+func SignalClientEnv() string {
+	return envSignalServer + "=" + SignalClientAddr()
+}
+
+func LogLevel() string {
+	return valueOrDefault(Global.Settings.LogLevel, DefaultLogLevel)
+}
+
+//
+//
+//private
+//
+//
+
+func applyOverrides() {
+	s := &Global.Settings
+
+	//environment
+	if v, ok := os.LookupEnv(EnvHeadless); ok {
+		if b, err := parseBool(v); err == nil {
+			s.RunHeadless = b
+		} else {
+			println("Ignoring " + EnvHeadless + "=" + v + " (not true/false)")
+		}
+	}
+
+	if port := os.Getenv(envLegacyRPCPort); port != "" {
+		s.RPCAddr = ":" + port
+	}
+
+	setIfGiven(&s.RPCAddr, os.Getenv(EnvRPCAddr))
+	setIfGiven(&s.SignalAddr, os.Getenv(EnvSignalAddr))
+	setIfGiven(&s.LogLevel, os.Getenv(EnvLogLevel))
+
+	//command line
+	if overrides.Headless != nil {
+		s.RunHeadless = *overrides.Headless
+	}
+
+	setIfGiven(&s.RPCAddr, overrides.RPCAddr)
+	setIfGiven(&s.SignalAddr, overrides.SignalAddr)
+	setIfGiven(&s.LogLevel, overrides.LogLevel)
+}
+
+func setIfGiven(setting *string, value string) {
+	if value != "" {
+		*setting = value
+	}
+}
+
+func valueOrDefault(value, def string) string {
+	if value == "" {
+		return def
+	}
+
+	return value
+}
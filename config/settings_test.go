@@ -0,0 +1,34 @@
+package config
+
+import (
+	"os"
+	"testing"
+)
+
+func TestOverridePrecedence(t *testing.T) {
+	defer os.Unsetenv(EnvRPCAddr)
+	defer os.Unsetenv(EnvLogLevel)
+	defer SetOverrides(Overrides{})
+
+	Global = Config{Settings: Settings{RPCAddr: ":1000", LogLevel: "warn"}}
+	os.Setenv(EnvRPCAddr, ":2000")
+	os.Setenv(EnvLogLevel, "info")
+	yes := true
+	SetOverrides(Overrides{LogLevel: "error", Headless: &yes})
+
+	if RPCAddr() != ":2000" {
+		t.Fatalf("environment should win over file, got %s", RPCAddr())
+	}
+
+	if LogLevel() != "error" || !Global.Settings.RunHeadless {
+		t.Fatalf("flags should win over everything, got %+v", Global.Settings)
+	}
+
+	Global = Config{}
+	os.Unsetenv(EnvRPCAddr)
+	SetOverrides(Overrides{})
+
+	if RPCAddr() != DefaultRPCAddr || SignalClientAddr() != "localhost:7999" {
+		t.Fatalf("wrong defaults: %s %s", RPCAddr(), SignalClientAddr())
+	}
+}
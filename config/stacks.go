@@ -0,0 +1,159 @@
+package config
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+const defaultReadyTimeout = 10 * time.Second
+
+//a group of apps which are started (& stopped) together
+type Stack struct {
+	Desc string     `yaml:"desc"`
+	Apps []StackApp `yaml:"apps"`
+}
+
+type StackApp struct {
+	Name      string     `yaml:"name"` //unique within the stack, used by depends_on
+	App       string     `yaml:"app"`  //key in "apps:" (default: same as name)
+	Args      []string   `yaml:"args"` //as typed after "start <app>"
+	DependsOn []string   `yaml:"depends_on"`
+	Ready     ReadyCheck `yaml:"ready"`
+}
+
+//how to tell that an app is up, before starting the ones depending on it.
+//when several checks are given, all of them have to pass
+type ReadyCheck struct {
+	Ping    bool          `yaml:"ping"`    //app answers a signal Ping
+	TCP     string        `yaml:"tcp"`     //host:port accepts connections
+	Output  string        `yaml:"output"`  //regex matching something the app prints
+	Timeout time.Duration `yaml:"timeout"` //per app (default: 10s)
+}
+
+func (sa *StackApp) AppName() string {
+	if sa.App == "" {
+		return sa.Name
+	}
+
+	return sa.App
+}
+
+func (rc *ReadyCheck) IsSet() bool {
+	return rc.Ping || rc.TCP != "" || rc.Output != ""
+}
+
+func (rc *ReadyCheck) TimeoutOrDefault() time.Duration {
+	if rc.Timeout <= 0 {
+		return defaultReadyTimeout
+	}
+
+	return rc.Timeout
+}
+
+func StackExistsWithName(name string) bool {
+	_, exists := Global.Stacks[name]
+	return exists
+}
+
+//the stack's apps in an order where every app comes after
+//everything it depends on.  also checks the stack is usable at all
+func (s Stack) StartOrder() ([]StackApp, error) {
+	return s.startOrderWith(Global.Apps)
+}
+
+func StackNames() []string {
+	names := make([]string, 0, len(Global.Stacks))
+
+	for name := range Global.Stacks {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+//
+//
+//private
+//
+//
+
+func (s Stack) startOrderWith(apps map[string]App) ([]StackApp, error) {
+	byName := make(map[string]int)
+
+	for i, sa := range s.Apps {
+		if sa.Name == "" {
+			return nil, fmt.Errorf("app #%d has no name", i+1)
+		}
+
+		if _, dup := byName[sa.Name]; dup {
+			return nil, fmt.Errorf("\"%s\" is listed twice", sa.Name)
+		}
+
+		if _, exists := apps[sa.AppName()]; !exists {
+			return nil, fmt.Errorf("%s: no app called \"%s\"", sa.Name, sa.AppName())
+		}
+
+		if sa.Ready.Output != "" {
+			if _, err := regexp.Compile(sa.Ready.Output); err != nil {
+				return nil, fmt.Errorf("%s: bad output regex: %v", sa.Name, err)
+			}
+		}
+
+		byName[sa.Name] = i
+	}
+
+	for _, sa := range s.Apps {
+		for _, dep := range sa.DependsOn {
+			if _, exists := byName[dep]; !exists {
+				return nil, fmt.Errorf("%s depends on unknown \"%s\"", sa.Name, dep)
+			}
+		}
+	}
+
+	//depth first, keeping the listed order wherever dependencies allow
+	const (
+		unvisited = iota
+		visiting
+		done
+	)
+
+	state := make([]int, len(s.Apps))
+	order := make([]StackApp, 0, len(s.Apps))
+
+	var visit func(i int, path []string) error
+	visit = func(i int, path []string) error {
+		sa := s.Apps[i]
+		path = append(path, sa.Name)
+
+		switch state[i] {
+		case done:
+			return nil
+		case visiting:
+			return fmt.Errorf("dependency cycle: %s", strings.Join(path, " -> "))
+		}
+
+		state[i] = visiting
+
+		for _, dep := range sa.DependsOn {
+			if err := visit(byName[dep], path); err != nil {
+				return err
+			}
+		}
+
+		state[i] = done
+		order = append(order, sa)
+		return nil
+	}
+
+	for i := range s.Apps {
+		if err := visit(i, nil); err != nil {
+			return nil, err
+		}
+	}
+
+	return order, nil
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestStartOrder(t *testing.T) {
+	Global = Config{Apps: map[string]App{"node": {}, "client": {}}}
+
+	s := Stack{Apps: []StackApp{
+		{Name: "socks", App: "client", DependsOn: []string{"node"}},
+		{Name: "vpn", App: "client", DependsOn: []string{"node", "socks"}},
+		{Name: "node"},
+	}}
+
+	order, err := s.StartOrder()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	names := ""
+	for _, sa := range order {
+		names += sa.Name + " "
+	}
+
+	if names != "node socks vpn " {
+		t.Fatalf("wrong order: %s", names)
+	}
+
+	bad := []Stack{
+		{Apps: []StackApp{{Name: "node", DependsOn: []string{"nope"}}}},
+		{Apps: []StackApp{{Name: "nope"}}},
+		{Apps: []StackApp{{Name: "node"}, {Name: "node"}}},
+		{Apps: []StackApp{
+			{Name: "node", DependsOn: []string{"client"}},
+			{Name: "client", DependsOn: []string{"node"}},
+		}},
+	}
+
+	for _, s := range bad {
+		if _, err := s.StartOrder(); err == nil {
+			t.Fatalf("%+v: no error", s.Apps)
+		}
+	}
+}
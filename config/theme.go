@@ -0,0 +1,288 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+const (
+	DefaultThemeName = "default"
+
+	CursorStyleBlock     = "block"
+	CursorStyleUnderline = "underline"
+	CursorStyleBar       = "bar"
+)
+
+//the "theme:" section.  colors are "#rgb", "#rrggbb", "#rrggbbaa"
+//or a name (see colorNames), & anything left out comes from "base"
+type Theme struct {
+	Base string `yaml:"base"` //one of the built-in themes (default: "default")
+
+	Desktop         string `yaml:"desktop"` //background behind all windows
+	WindowFocused   string `yaml:"window_focused"`
+	WindowUnfocused string `yaml:"window_unfocused"`
+	FrameFocused    string `yaml:"frame_focused"` //border & id tab
+	FrameUnfocused  string `yaml:"frame_unfocused"`
+	TextFocused     string `yaml:"text_focused"`
+	TextUnfocused   string `yaml:"text_unfocused"`
+	Cursor          string `yaml:"cursor"`
+	CursorStyle     string `yaml:"cursor_style"` //block, underline or bar
+	CursorBlink     *bool  `yaml:"cursor_blink"`
+	Selection       string `yaml:"selection"` //behind text selected by mouse
+
+	TaskbarBackground   string `yaml:"taskbar_background"`
+	TaskbarButton       string `yaml:"taskbar_button"`
+	TaskbarButtonActive string `yaml:"taskbar_button_active"` //focused/hovered/open
+	TaskbarText         string `yaml:"taskbar_text"`
+	TaskbarTextActive   string `yaml:"taskbar_text_active"`
+}
+
+type Color [4]float32 //RGBA, 0 to 1
+
+//a Theme with everything filled in & parsed, ready for drawing.
+//(field names match the color fields of Theme)
+type Palette struct {
+	Desktop         Color
+	WindowFocused   Color
+	WindowUnfocused Color
+	FrameFocused    Color
+	FrameUnfocused  Color
+	TextFocused     Color
+	TextUnfocused   Color
+	Cursor          Color
+	CursorStyle     string
+	CursorBlink     bool
+	Selection       Color
+
+	TaskbarBackground   Color
+	TaskbarButton       Color
+	TaskbarButtonActive Color
+	TaskbarText         Color
+	TaskbarTextActive   Color
+}
+
+var blinking, steady = true, false
+
+var builtinThemes = map[string]Theme{
+	//how viscript always looked
+	DefaultThemeName: {
+		Desktop:         "gray_medium", //(the clear color it always had)
+		WindowFocused:   "white",
+		WindowUnfocused: "gray",
+		FrameFocused:    "white",
+		FrameUnfocused:  "gray",
+		TextFocused:     "white",
+		TextUnfocused:   "gray",
+		Cursor:          "white",
+		CursorStyle:     CursorStyleBlock,
+		CursorBlink:     &blinking,
+		Selection:       "#405080",
+
+		TaskbarBackground:   "gray",
+		TaskbarButton:       "gray",
+		TaskbarButtonActive: "white",
+		TaskbarText:         "gray",
+		TaskbarTextActive:   "white",
+	},
+
+	"dark": {
+		Desktop:         "#101014",
+		WindowFocused:   "gray_light",
+		WindowUnfocused: "gray_dark",
+		FrameFocused:    "#5080c0",
+		FrameUnfocused:  "gray",
+		TextFocused:     "#e0e0e0",
+		TextUnfocused:   "gray_light",
+		Cursor:          "#5080c0",
+		CursorStyle:     CursorStyleBar,
+		CursorBlink:     &blinking,
+		Selection:       "#304870",
+
+		TaskbarBackground:   "gray_dark",
+		TaskbarButton:       "gray_dark",
+		TaskbarButtonActive: "#5080c0",
+		TaskbarText:         "gray_light",
+		TaskbarTextActive:   "white",
+	},
+
+	//nothing but black, white & yellow, & a cursor that stays put
+	"high-contrast": {
+		Desktop:         "black",
+		WindowFocused:   "black",
+		WindowUnfocused: "black",
+		FrameFocused:    "yellow",
+		FrameUnfocused:  "white",
+		TextFocused:     "white",
+		TextUnfocused:   "white",
+		Cursor:          "yellow",
+		CursorStyle:     CursorStyleBlock,
+		CursorBlink:     &steady,
+		Selection:       "blue",
+
+		TaskbarBackground:   "black",
+		TaskbarButton:       "black",
+		TaskbarButtonActive: "yellow",
+		TaskbarText:         "white",
+		TaskbarTextActive:   "black",
+	},
+}
+
+//same values as the ones in viewport/gl/materials.go
+var colorNames = map[string]Color{
+	"black":       {0, 0, 0, 1},
+	"blue":        {0, 0, 1, 1},
+	"cyan":        {0, 0.5, 1, 1},
+	"gray":        {0.25, 0.25, 0.25, 1},
+	"gray_dark":   {0.15, 0.15, 0.15, 1},
+	"gray_light":  {0.4, 0.4, 0.4, 1},
+	"gray_medium": {0.5, 0.5, 0.5, 1},
+	"green":       {0, 1, 0, 1},
+	"magenta":     {1, 0, 1, 1},
+	"orange":      {0.8, 0.35, 0, 1},
+	"purple":      {0.6, 0, 0.8, 1},
+	"red":         {1, 0, 0, 1},
+	"white":       {1, 1, 1, 1},
+	"yellow":      {1, 1, 0, 1},
+}
+
+func ThemeNames() []string {
+	names := make([]string, 0, len(builtinThemes))
+
+	for name := range builtinThemes {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func ParseColor(s string) (Color, error) {
+	s = strings.ToLower(strings.TrimSpace(s))
+
+	if c, ok := colorNames[s]; ok {
+		return c, nil
+	}
+
+	hex := strings.TrimPrefix(s, "#")
+	if hex == s {
+		return Color{}, fmt.Errorf("unknown color \"%s\" (use #rrggbb or a name)", s)
+	}
+
+	if len(hex) == 3 { //#rgb
+		hex = string([]byte{hex[0], hex[0], hex[1], hex[1], hex[2], hex[2]})
+	}
+
+	if len(hex) == 6 {
+		hex += "ff"
+	}
+
+	n, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil || len(hex) != 8 {
+		return Color{}, fmt.Errorf("bad color \"%s\" (use #rgb, #rrggbb or #rrggbbaa)", s)
+	}
+
+	c := Color{}
+
+	for i := 0; i < 4; i++ {
+		c[i] = float32((n>>uint(24-8*i))&0xff) / 255
+	}
+
+	return c, nil
+}
+
+//the theme on top of its base, parsed
+func (t Theme) Resolve() (Palette, error) {
+	var p Palette
+	var first error
+
+	t.eachField(func(key string, err error) {
+		if first == nil {
+			first = errors.New("theme " + key + ": " + err.Error())
+		}
+	}, &p)
+
+	return p, first
+}
+
+//
+//
+//private
+//
+//
+
+//fills in p (when given) & reports every problem by yaml key.
+//fields of Palette are found by the names of Theme's fields
+func (t Theme) eachField(report func(key string, err error), p *Palette) {
+	baseName := t.Base
+	if baseName == "" {
+		baseName = DefaultThemeName
+	}
+
+	base, exists := builtinThemes[baseName]
+	if !exists {
+		report("base", fmt.Errorf("no built-in theme \"%s\" (there's %s)",
+			baseName, strings.Join(ThemeNames(), ", ")))
+		base = builtinThemes[DefaultThemeName]
+	}
+
+	own := reflect.ValueOf(t)
+	from := reflect.ValueOf(base)
+	var to reflect.Value
+
+	if p != nil {
+		to = reflect.ValueOf(p).Elem()
+	}
+
+	for i := 0; i < own.NumField(); i++ {
+		field := own.Type().Field(i)
+		key := strings.Split(field.Tag.Get("yaml"), ",")[0]
+
+		switch field.Name {
+		case "Base":
+			continue
+
+		case "CursorStyle":
+			style := valueOrDefault(t.CursorStyle, base.CursorStyle)
+
+			switch style {
+			case CursorStyleBlock, CursorStyleUnderline, CursorStyleBar:
+			default:
+				report(key, fmt.Errorf("\"%s\" isn't block, underline or bar", style))
+				style = CursorStyleBlock
+			}
+
+			if p != nil {
+				p.CursorStyle = style
+			}
+
+		case "CursorBlink":
+			blink := base.CursorBlink
+			if t.CursorBlink != nil {
+				blink = t.CursorBlink
+			}
+
+			if p != nil {
+				p.CursorBlink = *blink
+			}
+
+		default: //a color
+			value := own.Field(i).String()
+			if value == "" {
+				value = from.Field(i).String()
+			}
+
+			c, err := ParseColor(value)
+			if err != nil {
+				report(key, err)
+			}
+
+			if p != nil {
+				to.FieldByName(field.Name).Set(reflect.ValueOf(c))
+			}
+		}
+	}
+}
@@ -0,0 +1,60 @@
+package config
+
+import (
+	"testing"
+)
+
+func TestParseColor(t *testing.T) {
+	tests := map[string]Color{
+		"#fff":      {1, 1, 1, 1},
+		"#FF0000":   {1, 0, 0, 1},
+		"#00000000": {0, 0, 0, 0},
+		"gray":      {0.25, 0.25, 0.25, 1},
+	}
+
+	for s, want := range tests {
+		if c, err := ParseColor(s); err != nil || c != want {
+			t.Fatalf("%s: got %v, %v", s, c, err)
+		}
+	}
+
+	for _, s := range []string{"", "fff", "#ff", "#gggggg", "grey"} {
+		if _, err := ParseColor(s); err == nil {
+			t.Fatalf("%s: expected an error", s)
+		}
+	}
+}
+
+func TestThemeResolve(t *testing.T) {
+	blink := true
+	p, err := Theme{Base: "high-contrast", Cursor: "#f00", CursorBlink: &blink}.Resolve()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if p.Cursor != (Color{1, 0, 0, 1}) || !p.CursorBlink {
+		t.Fatal("overrides weren't applied:", p)
+	}
+
+	if p.Desktop != (Color{0, 0, 0, 1}) || p.CursorStyle != CursorStyleBlock {
+		t.Fatal("base wasn't applied:", p)
+	}
+
+	//without a theme, things look like they did before themes
+	p, err = Theme{}.Resolve()
+	if err != nil || p.Desktop != (Color{0.5, 0.5, 0.5, 1}) {
+		t.Fatal("default desktop changed:", p.Desktop, err)
+	}
+
+	for _, theme := range []Theme{{Base: "nope"}, {TextFocused: "#12"}, {CursorStyle: "beam"}} {
+		if _, err := theme.Resolve(); err == nil {
+			t.Fatalf("%+v: expected an error", theme)
+		}
+	}
+
+	for _, name := range ThemeNames() {
+		if _, err := (Theme{Base: name}).Resolve(); err != nil {
+			t.Fatal(err)
+		}
+	}
+}
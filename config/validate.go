@@ -0,0 +1,334 @@
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v2"
+)
+
+var logLevels = []string{"panic", "fatal", "error", "warn", "warning", "info", "debug"}
+
+type Problem struct {
+	Line    int //0 when it's not about a specific line
+	Message string
+	Warning bool //viscript still starts, but something won't work
+}
+
+func (p Problem) String() string {
+	s := p.Message
+
+	if p.Line > 0 {
+		s = "line " + strconv.Itoa(p.Line) + ": " + s
+	}
+
+	if p.Warning {
+		s = "warning: " + s
+	}
+
+	return s
+}
+
+//everything wrong with a config file, in order of appearance
+type Problems []Problem
+
+func (ps Problems) Error() string {
+	lines := make([]string, len(ps))
+
+	for i, p := range ps {
+		lines[i] = p.String()
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+func (ps Problems) HasErrors() bool {
+	for _, p := range ps {
+		if !p.Warning {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (ps Problems) Warnings() (warnings Problems) {
+	for _, p := range ps {
+		if p.Warning {
+			warnings = append(warnings, p)
+		}
+	}
+
+	return warnings
+}
+
+//reads & checks a config file, without making it the global one.
+//err is only set when the file can't be read or isn't yaml at all
+func Parse(path string) (conf Config, problems Problems, err error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return conf, nil, err
+	}
+
+	lines := indexLines(data)
+
+	err = yaml.UnmarshalStrict(data, &conf)
+	if typeErr, ok := err.(*yaml.TypeError); ok {
+		//decoding went on past these, so the rest can still be checked
+		for _, e := range typeErr.Errors {
+			problems = append(problems, decodeProblem(e, lines))
+		}
+	} else if err != nil {
+		return conf, nil, err
+	}
+
+	v := validator{conf: &conf, lines: lines}
+	v.checkApps()
+	v.checkStacks()
+	v.checkSettings()
+	v.checkTheme()
+	v.checkKeybindings()
+	problems = append(problems, v.problems...)
+
+	sort.SliceStable(problems, func(i, j int) bool {
+		return problems[i].Line < problems[j].Line
+	})
+
+	return conf, problems, nil
+}
+
+//
+//
+//private
+//
+//
+
+var unknownFieldPattern = regexp.MustCompile(`^line (\d+): field (\S+) not found in (?:struct|type) config\.(\w+)$`)
+var lineMessagePattern = regexp.MustCompile(`^line (\d+): (.*)$`)
+
+func decodeProblem(e string, lines lineIndex) Problem {
+	//yaml.v2 reports the line where the surrounding mapping starts
+	if m := unknownFieldPattern.FindStringSubmatch(e); m != nil {
+		line, _ := strconv.Atoi(m[1])
+
+		if keyLine := lines.keyLineAfter(line, m[2]); keyLine != 0 {
+			line = keyLine
+		}
+
+		return Problem{Line: line, Message: fmt.Sprintf(
+			"unknown key \"%s\" (in %s)", m[2], strings.ToLower(m[3]))}
+	}
+
+	if m := lineMessagePattern.FindStringSubmatch(e); m != nil {
+		line, _ := strconv.Atoi(m[1])
+		return Problem{Line: line, Message: m[2]}
+	}
+
+	return Problem{Message: e}
+}
+
+type validator struct {
+	conf     *Config
+	lines    lineIndex
+	problems Problems
+}
+
+func (v *validator) errorAt(path []string, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{
+		Line: v.lines.lineOf(path...), Message: fmt.Sprintf(format, args...)})
+}
+
+func (v *validator) warningAt(path []string, format string, args ...interface{}) {
+	v.problems = append(v.problems, Problem{
+		Line: v.lines.lineOf(path...), Message: fmt.Sprintf(format, args...), Warning: true})
+}
+
+//a path with a separator is relative to the app's cwd (where it's started),
+//not viscript's.  (bare names are looked up in $PATH)
+func (a *App) pathFromViscript() string {
+	if a.Cwd == "" || filepath.IsAbs(a.Path) || !strings.ContainsAny(a.Path, "/"+string(filepath.Separator)) {
+		return a.Path
+	}
+
+	return filepath.Join(a.ExpandedCwd(), a.Path)
+}
+
+func (v *validator) checkApps() {
+	for name, a := range v.conf.Apps {
+		at := func(keys ...string) []string {
+			return append([]string{"apps", name}, keys...)
+		}
+
+		if a.Path == "" {
+			v.errorAt(at(), "app \"%s\" has no path", name)
+		} else if _, err := exec.LookPath(a.pathFromViscript()); err != nil {
+			v.warningAt(at("path"), "app \"%s\": %s is not an executable (%v)",
+				name, a.Path, unwrapExecError(err))
+		}
+
+		if a.Cwd != "" {
+			if info, err := os.Stat(a.ExpandedCwd()); err != nil || !info.IsDir() {
+				v.warningAt(at("cwd"), "app \"%s\": cwd %s is not a directory",
+					name, a.ExpandedCwd())
+			}
+		}
+
+		if a.Limits.Nice < -20 || a.Limits.Nice > 19 {
+			v.errorAt(at("limits", "nice"), "app \"%s\": nice must be -20 to 19", name)
+		}
+
+		v.checkParams(name, &a)
+	}
+}
+
+func (v *validator) checkParams(appName string, a *App) {
+	seen := make(map[string]bool)
+	optional := "" //the last optional param without a default
+
+	for i, p := range a.Params {
+		at := []string{"apps", appName, "params", strconv.Itoa(i)}
+
+		if p.Name == "" {
+			v.errorAt(at, "app \"%s\": param #%d has no name", appName, i+1)
+			continue
+		}
+
+		if seen[strings.ToLower(p.Name)] {
+			v.errorAt(at, "app \"%s\": param \"%s\" is listed twice", appName, p.Name)
+		}
+
+		seen[strings.ToLower(p.Name)] = true
+
+		switch p.TypeOrDefault() {
+		case ParamTypeHostPort, ParamTypeBool, ParamTypeInt, ParamTypeString:
+		default:
+			v.errorAt(append(at, "type"), "app \"%s\": param \"%s\" has unknown type \"%s\" "+
+				"(use host:port, bool, int or string)", appName, p.Name, p.Type)
+			continue
+		}
+
+		if p.Default != "" && !strings.Contains(p.Default, FreePortPlaceholder) {
+			if _, err := p.Check(p.Default); err != nil {
+				v.errorAt(append(at, "default"), "app \"%s\": bad default for %v",
+					appName, err)
+			}
+		}
+
+		//args are positional, so this one could never be left out
+		if p.Required && optional != "" {
+			v.errorAt(at, "app \"%s\": required param \"%s\" comes after \"%s\", "+
+				"which is optional & has no default", appName, p.Name, optional)
+		}
+
+		if !p.Required && p.Default == "" {
+			optional = p.Name
+		}
+	}
+}
+
+func (v *validator) checkStacks() {
+	for name, s := range v.conf.Stacks {
+		if len(s.Apps) == 0 {
+			v.errorAt([]string{"stacks", name}, "stack \"%s\" has no apps", name)
+			continue
+		}
+
+		if _, err := s.startOrderWith(v.conf.Apps); err != nil {
+			v.errorAt([]string{"stacks", name}, "stack \"%s\": %v", name, err)
+		}
+
+		for i, sa := range s.Apps {
+			at := []string{"stacks", name, "apps", strconv.Itoa(i)}
+
+			a, exists := v.conf.Apps[sa.AppName()]
+			if exists && len(a.Params) > 0 {
+				if _, err := a.ResolveArgs(sa.Args); err != nil {
+					v.errorAt(append(at, "args"), "stack \"%s\", %s: %v", name, sa.Name, err)
+				}
+			}
+
+			if sa.Ready.TCP != "" {
+				if _, _, err := net.SplitHostPort(sa.Ready.TCP); err != nil {
+					v.errorAt(append(at, "ready", "tcp"), "stack \"%s\", %s: "+
+						"tcp check needs host:port", name, sa.Name)
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) checkSettings() {
+	s := &v.conf.Settings
+	at := func(key string) []string {
+		return []string{"settings", key}
+	}
+
+	if s.LogLevel != "" && !contains(logLevels, strings.ToLower(s.LogLevel)) {
+		v.errorAt(at("logLevel"), "logLevel must be one of: %s",
+			strings.Join(logLevels, ", "))
+	}
+
+	if s.RPCAddr != "" {
+		if _, _, err := net.SplitHostPort(s.RPCAddr); err != nil {
+			v.errorAt(at("rpcAddr"), "rpcAddr must be host:port or :port")
+		}
+	}
+
+	if s.SignalAddr != "" {
+		if _, _, err := net.SplitHostPort(s.SignalAddr); err != nil {
+			v.errorAt(at("signalAddr"), "signalAddr must be host:port or :port")
+		}
+	}
+
+	if s.StopGracePeriod < 0 {
+		v.errorAt(at("stopGracePeriod"), "stopGracePeriod can't be negative")
+	}
+
+	if s.WatchConfig < 0 {
+		v.errorAt(at("watchConfig"), "watchConfig can't be negative")
+	}
+}
+
+func (v *validator) checkTheme() {
+	v.conf.Theme.eachField(func(key string, err error) {
+		v.errorAt([]string{"theme", key}, "theme %s: %v", key, err)
+	}, nil)
+}
+
+func (v *validator) checkKeybindings() {
+	v.conf.Keys.resolve(func(key string, err error) {
+		at := []string{"keybindings", "bindings", key}
+		if key == "leader" {
+			at = []string{"keybindings", "leader"}
+		}
+
+		v.errorAt(at, "keybindings: %v", err)
+	})
+}
+
+//exec.Error repeats the name, which the message already contains
+func unwrapExecError(err error) error {
+	if e, ok := err.(*exec.Error); ok {
+		return e.Err
+	}
+
+	return err
+}
+
+func contains(list []string, s string) bool {
+	for _, item := range list {
+		if item == s {
+			return true
+		}
+	}
+
+	return false
+}
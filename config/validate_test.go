@@ -0,0 +1,83 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"testing"
+)
+
+const invalidConfig = `apps:
+  sh:
+    path: /bin/sh
+    help: |
+      path: not a key
+    params:
+      - name: a
+        type: intt
+  nopath:
+    colour: red
+  missing:
+    path: /no/such/binary
+settings:
+  logLevel: loud
+`
+
+func TestParseReportsEveryProblem(t *testing.T) {
+	f, err := ioutil.TempFile("", "viscript-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+	f.WriteString(invalidConfig)
+	f.Close()
+
+	_, problems, err := Parse(f.Name())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	want := map[int]bool{ //line to whether it's a warning
+		8:  false, //unknown param type
+		9:  false, //no path
+		10: false, //unknown key
+		12: true,  //not an executable
+		14: false, //bad log level
+	}
+
+	if len(problems) != len(want) {
+		t.Fatalf("expected %d problems, got:\n%v", len(want), problems)
+	}
+
+	for _, p := range problems {
+		warning, ok := want[p.Line]
+		if !ok || warning != p.Warning {
+			t.Fatalf("unexpected: %s", p)
+		}
+	}
+}
+
+func TestRelativePathIsFromCwd(t *testing.T) {
+	dir, err := ioutil.TempDir("", "viscript-app")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.RemoveAll(dir)
+	os.Mkdir(dir+"/bin", 0755)
+	ioutil.WriteFile(dir+"/bin/node", []byte("#!/bin/sh\n"), 0755)
+
+	f, err := ioutil.TempFile("", "viscript-config")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	defer os.Remove(f.Name())
+	f.WriteString("apps:\n  node:\n    path: ./bin/node\n    cwd: " + dir + "\n")
+	f.Close()
+
+	_, problems, err := Parse(f.Name())
+	if err != nil || len(problems) != 0 {
+		t.Fatalf("expected no problems, got: %v\n%v", err, problems)
+	}
+}
@@ -47,7 +47,13 @@ func Init() {
 
 			tc := msg.MessageTokenizedCommand{tokens[0], args}
 			m := msg.Serialize(msg.TypeTokenizedCommand, tc)
-			terminal.Terms.TermMap[terminal.Terms.FocusedId].RelayToTask(m)
+
+			//(the last terminal may have been closed)
+			if terminal.Terms.GetFocusedTerminal() == nil {
+				terminal.Terms.Add()
+			}
+
+			terminal.Terms.GetFocusedTerminal().RelayToTask(m)
 		}
 
 		close(ch)
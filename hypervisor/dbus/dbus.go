@@ -59,6 +59,17 @@ func (di *DbusInstance) Init() {
 	di.Resources = make([]ResourceMeta, 0)
 }
 
+func (di *DbusInstance) Teardown() {
+	println("<dbus/registry>.Teardown()")
+
+	for id := range di.PubsubChannels {
+		di.RemoveChannel(id)
+	}
+
+	di.PubsubChannels = nil
+	di.Resources = nil
+}
+
 //
 //
 //
@@ -32,7 +32,11 @@ func (di *DbusInstance) PublishTo(chanId uint32, msg []byte) {
 	//println("<dbus/pubsub>.PublishTo()", id)
 	id := ChannelId(chanId)
 
-	channel := di.PubsubChannels[id]
+	channel, ok := di.PubsubChannels[id]
+	if !ok { //removed (or everything is shutting down)
+		return
+	}
+
 	di.prefixMessageWithChanId(id, &msg)
 
 	//FIXME? fix non-determinism?
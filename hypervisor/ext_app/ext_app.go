@@ -10,22 +10,33 @@ import (
 
 	"strings"
 
+	"os"
 	"os/exec"
+	"regexp"
+	"time"
 
 	"fmt"
 
 	"strconv"
 
 	"github.com/skycoin/viscript/app"
+	"github.com/skycoin/viscript/config"
 	"github.com/skycoin/viscript/msg"
 )
 
-const path = "hypervisor/ext_app/ext_app"
+const (
+	path = "hypervisor/ext_app/ext_app"
+
+	maxWatchedOutput = 8192 //bytes kept around by PollOutput
+)
 
 type ExternalApp struct {
 	Id          msg.ExternalAppId
+	Name        string //as in config
 	CommandLine string
 
+	conf config.App //copied at creation, so config changes won't affect it
+
 	TaskIn   chan []byte
 	TaskOut  chan []byte
 	TaskExit chan struct{} //this way it's easy to cleanup multiple places
@@ -37,7 +48,13 @@ type ExternalApp struct {
 	stdOutPipe io.ReadCloser
 	stdInPipe  io.WriteCloser
 
-	shutdown chan struct{}
+	shutdown   chan struct{}
+	closeMutex sync.Mutex //(shutdown & TaskExit can be closed from several routines)
+
+	exited     chan struct{} //closed once the OS reports the process is gone
+	exitStatus string        //how it ended (only valid after 'exited' is closed)
+	stopping   bool          //Stop() was called
+	watched    []byte        //tail of what PollOutput has read so far
 
 	routinesStarted bool
 
@@ -45,9 +62,11 @@ type ExternalApp struct {
 }
 
 //non-instanced
-func MakeNewExternalApp(tokens []string, detached bool) (*ExternalApp, error) {
+func MakeNewExternalApp(name string, tokens []string, detached bool) (*ExternalApp, error) {
 	app.At(path, "MakeNewExternalApp")
 	var ea ExternalApp
+	ea.Name = name
+	ea.conf = config.Global.Apps[name]
 
 	err := ea.Init(tokens)
 	if err != nil {
@@ -79,6 +98,24 @@ func (ea *ExternalApp) Init(tokens []string) error {
 		return err
 	}
 
+	//it asked for limits, so don't start it without them
+	if ea.conf.Limits.IsSet() {
+		if err = wrapWithLimits(ea.cmd, ea.conf.Limits); err != nil {
+			return errors.New("Couldn't apply limits: " + err.Error())
+		}
+	}
+
+	setProcessGroup(ea.cmd)
+	ea.cmd.Dir = ea.conf.ExpandedCwd()
+	ea.cmd.Env = ea.conf.BuildEnv()
+
+	if ea.cmd.Env == nil {
+		ea.cmd.Env = os.Environ()
+	}
+
+	//first, so the app's own env (from config) can still override it
+	ea.cmd.Env = append([]string{config.SignalClientEnv()}, ea.cmd.Env...)
+
 	if ea.stdOutPipe, err = ea.cmd.StdoutPipe(); err != nil {
 		return err
 	}
@@ -97,6 +134,7 @@ func (ea *ExternalApp) Init(tokens []string) error {
 	ea.TaskExit = make(chan struct{})
 
 	ea.shutdown = make(chan struct{})
+	ea.exited = make(chan struct{})
 
 	ea.routinesStarted = false
 
@@ -119,14 +157,16 @@ func (ea *ExternalApp) createCMDAccordingToOS(tokens []string) (*exec.Cmd, error
 
 func (ea *ExternalApp) cmdInRoutine() {
 	app.At(path, "cmdInRoutine")
+	defer ea.wg.Done()
+	pipe := ea.stdOutPipe //(TearDown forgets it while this may still be reading)
 
 	for {
 		buf := make([]byte, 2048)
-		size, err := ea.stdOutPipe.Read(buf[:])
+		size, err := pipe.Read(buf[:])
 		if err != nil {
 			println("Cmd In Routine error:", err.Error())
-			close(ea.TaskExit)
-			close(ea.shutdown)
+			ea.closeUnlessClosed(ea.TaskExit)
+			ea.stopRoutines()
 			return
 		}
 
@@ -143,20 +183,26 @@ func (ea *ExternalApp) cmdInRoutine() {
 
 func (ea *ExternalApp) cmdOutRoutine() {
 	app.At(path, "cmdOutRoutine")
+	defer ea.wg.Done()
+	pipe := ea.stdInPipe
 
 	for {
 		select {
 		case <-ea.shutdown:
 			println("!!! Shutting cmdOutRoutine down !!!")
 			return
-		case data := <-ea.cmdOut:
+		case data, ok := <-ea.cmdOut:
+			if !ok { //torn down
+				return
+			}
+
 			fmt.Printf("-- Received input to write to external app: %s\n",
 				string(data))
-			_, err := ea.stdInPipe.Write(append(data, '\n'))
+			_, err := pipe.Write(append(data, '\n'))
 			if err != nil {
 				println("!!! Couldn't Write To the std in pipe of the task!!!")
-				close(ea.TaskExit)
-				close(ea.shutdown)
+				ea.closeUnlessClosed(ea.TaskExit)
+				ea.stopRoutines()
 				return
 			}
 		}
@@ -190,8 +236,98 @@ func (ea *ExternalApp) startRoutines() error {
 	return nil
 }
 
+//reaps the process (so it doesn't linger as a zombie) & records how it ended.
+//os.Process.Wait is used instead of exec.Cmd.Wait, because the latter
+//closes the stdout pipe which cmdInRoutine may still be reading
+func (ea *ExternalApp) waitRoutine(p *os.Process) {
+	state, err := p.Wait()
+	if err != nil {
+		ea.exitStatus = err.Error()
+	} else {
+		ea.exitStatus = state.String()
+	}
+
+	close(ea.exited)
+}
+
+//(started by Stop)
+func (ea *ExternalApp) killAfterGracePeriod(p *os.Process, grace time.Duration) {
+	select {
+	case <-ea.exited:
+	case <-time.After(grace):
+		println("External app", ea.Id, "still running after", grace.String(), "- sending SIGKILL")
+		signalGroup(p, os.Kill)
+	}
+}
+
+func (ea *ExternalApp) hasExited() bool {
+	select {
+	case <-ea.exited:
+		return true
+	default:
+		return false
+	}
+}
+
+//whether the app printed something matching re, reading only what's
+//already there (it's called every frame, till it matches).  starts reading
+//its stdout if nothing does yet.  what's read is passed on (as far as
+//there's room), so a terminal attaching later still shows it
+func (ea *ExternalApp) PollOutput(re *regexp.Regexp) (bool, error) {
+	err := ea.startRoutines()
+	if err != nil {
+		return false, err
+	}
+
+	for {
+		select {
+		case data := <-ea.cmdIn:
+			select {
+			case ea.TaskOut <- data:
+			default:
+			}
+
+			ea.watched = append(ea.watched, data...)
+			if re.Match(ea.watched) {
+				ea.watched = nil
+				return true, nil
+			}
+
+			//only keep the tail, a match could still span chunks
+			if len(ea.watched) > maxWatchedOutput {
+				ea.watched = ea.watched[len(ea.watched)-maxWatchedOutput/2:]
+			}
+		default:
+			return false, nil
+		}
+	}
+}
+
 func (ea *ExternalApp) stopRoutines() {
-	close(ea.shutdown)
+	ea.closeUnlessClosed(ea.shutdown)
+}
+
+func (ea *ExternalApp) closeUnlessClosed(c chan struct{}) {
+	ea.closeMutex.Lock()
+	defer ea.closeMutex.Unlock()
+
+	select {
+	case <-c:
+	default:
+		close(c)
+	}
+}
+
+//(started by TearDown).  the app may still print till it has ended, so
+//the channels its routines send on are only closed once they're done.
+//(TaskIn & cmdOut are left open, as they're sent on by the main loop)
+func (ea *ExternalApp) closeChannelsWhenEnded() {
+	<-ea.exited
+	ea.stopRoutines()
+	ea.wg.Wait() //(cmdInRoutine sees the end of the app's output)
+
+	close(ea.cmdIn)
+	close(ea.TaskOut) //lets an attached task know it ended
 }
 
 func (ea *ExternalApp) taskOutput() {
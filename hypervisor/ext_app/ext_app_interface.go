@@ -1,7 +1,11 @@
 package ext_app
 
 import (
+	"errors"
+	"os"
+
 	"github.com/skycoin/viscript/app"
+	"github.com/skycoin/viscript/config"
 	"github.com/skycoin/viscript/msg"
 )
 
@@ -20,19 +24,61 @@ func (ea *ExternalApp) Start() error {
 		return err
 	}
 
+	go ea.waitRoutine(ea.cmd.Process)
 	return nil
 }
 
-func (ea *ExternalApp) TearDown() {
-	app.At(path, "TearDown")
+//sends SIGTERM to the app's whole process group, & SIGKILL later if it's
+//still around after the grace period, without waiting for either (its
+//reaping shows up through GetExitStatus).  calling it again does nothing.
+//returns how the app ended, or that it's being stopped
+func (ea *ExternalApp) Stop() string {
+	app.At(path, "Stop")
+
+	if ea.hasExited() {
+		return "ended: " + ea.exitStatus
+	}
+
+	if ea.cmd == nil || ea.cmd.Process == nil {
+		return "not running"
+	}
+
+	grace := config.StopGracePeriod()
+	stopping := "stopping (SIGKILL after " + grace.String() + " if still running)"
+
+	if ea.stopping {
+		return stopping
+	}
+
+	ea.stopping = true
+
+	if err := signalGroup(ea.cmd.Process, terminateSignal); err != nil {
+		println("Couldn't signal process group:", err.Error())
+		ea.cmd.Process.Kill()
+	}
+
+	go ea.killAfterGracePeriod(ea.cmd.Process, grace)
+	return stopping
+}
 
-	ea.cmd.Process.Kill()
+//sends sig to the app's whole process group
+func (ea *ExternalApp) Signal(sig os.Signal) error {
+	if ea.cmd == nil || ea.cmd.Process == nil {
+		return errors.New("External app isn't running")
+	}
+
+	if ea.hasExited() {
+		return errors.New("External app already ended: " + ea.exitStatus)
+	}
+
+	return signalGroup(ea.cmd.Process, sig)
+}
 
-	close(ea.cmdIn)
-	close(ea.cmdOut)
+func (ea *ExternalApp) TearDown() {
+	app.At(path, "TearDown")
 
-	close(ea.TaskIn)
-	close(ea.TaskOut)
+	println("External app", ea.Id, ea.Stop()) //(no-op if it was already stopped)
+	go ea.closeChannelsWhenEnded()
 	// close(ea.TaskExit)
 
 	if ea.cmd != nil {
@@ -78,3 +124,20 @@ func (ea *ExternalApp) GetOutputChannel() chan []byte {
 func (ea *ExternalApp) GetExitChannel() chan struct{} {
 	return ea.TaskExit
 }
+
+//empty while the process is still running
+func (ea *ExternalApp) GetExitStatus() string {
+	if !ea.hasExited() {
+		return ""
+	}
+
+	return ea.exitStatus
+}
+
+func (ea *ExternalApp) GetProcessId() int {
+	if ea.cmd == nil || ea.cmd.Process == nil {
+		return 0
+	}
+
+	return ea.cmd.Process.Pid
+}
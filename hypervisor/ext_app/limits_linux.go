@@ -0,0 +1,53 @@
+//go:build linux
+// +build linux
+
+package ext_app
+
+import (
+	"fmt"
+	"os/exec"
+	"strings"
+
+	"github.com/skycoin/viscript/config"
+)
+
+const limitsShell = "/bin/sh"
+
+//Go can't run code between fork & exec, so the command is started
+//through a shell which sets the limits on itself, then execs the app
+//(keeping its pid & process group).  so the app never runs without
+//them, & anything it spawns inherits them.  if one can't be set, the
+//reason is printed to the app's stdout & it ends with status 126.
+//(except for nice, which only warns when it isn't allowed to go below 0)
+func wrapWithLimits(cmd *exec.Cmd, l config.Limits) error {
+	steps := []string{}
+
+	if l.MaxOpenFiles != 0 {
+		steps = append(steps, fmt.Sprintf("ulimit -n %d", l.MaxOpenFiles))
+	}
+
+	if l.MaxAddressSpace != 0 {
+		kb := (uint64(l.MaxAddressSpace) + 1023) / 1024
+		steps = append(steps, fmt.Sprintf("ulimit -v %d", kb))
+	}
+
+	if l.MaxCPUSeconds != 0 {
+		steps = append(steps, fmt.Sprintf("ulimit -t %d", l.MaxCPUSeconds))
+	}
+
+	for i := range steps {
+		steps[i] += " 2>&1" //(so a failure shows in an attached terminal)
+	}
+
+	run := `exec "$0" "$@"`
+	if l.Nice != 0 {
+		run = fmt.Sprintf(`exec nice -n %d "$0" "$@"`, l.Nice)
+	}
+
+	script := strings.Join(append(steps, run), " && ") + " || exit 126"
+
+	//the shell's $0 & $@ are the app's (already looked up) path & args
+	cmd.Args = append([]string{limitsShell, "-c", script, cmd.Path}, cmd.Args[1:]...)
+	cmd.Path = limitsShell
+	return nil
+}
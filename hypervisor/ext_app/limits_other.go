@@ -0,0 +1,15 @@
+//go:build !linux
+// +build !linux
+
+package ext_app
+
+import (
+	"errors"
+	"os/exec"
+
+	"github.com/skycoin/viscript/config"
+)
+
+func wrapWithLimits(cmd *exec.Cmd, l config.Limits) error {
+	return errors.New("Resource limits are only supported on linux")
+}
@@ -0,0 +1,59 @@
+//go:build !windows
+// +build !windows
+
+package ext_app
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+//what a graceful stop sends first
+var terminateSignal os.Signal = syscall.SIGTERM
+
+var signalsByName = map[string]syscall.Signal{
+	"HUP":  syscall.SIGHUP,
+	"INT":  syscall.SIGINT,
+	"QUIT": syscall.SIGQUIT,
+	"KILL": syscall.SIGKILL,
+	"USR1": syscall.SIGUSR1,
+	"USR2": syscall.SIGUSR2,
+	"TERM": syscall.SIGTERM,
+	"STOP": syscall.SIGSTOP,
+	"CONT": syscall.SIGCONT,
+}
+
+//accepts "TERM", "sigterm", "15" etc.
+func ParseSignal(s string) (os.Signal, error) {
+	if n, err := strconv.Atoi(s); err == nil {
+		return syscall.Signal(n), nil
+	}
+
+	name := strings.TrimPrefix(strings.ToUpper(s), "SIG")
+
+	if sig, ok := signalsByName[name]; ok {
+		return sig, nil
+	}
+
+	return nil, errors.New("Unknown signal \"" + s + "\"")
+}
+
+//the app becomes leader of its own group, so anything it spawns
+//(e.g. via a wrapper script) can be signalled together with it
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setpgid: true}
+}
+
+func signalGroup(p *os.Process, sig os.Signal) error {
+	s, ok := sig.(syscall.Signal)
+	if !ok {
+		return errors.New("Unsupported signal: " + sig.String())
+	}
+
+	//negative pid means the whole process group
+	return syscall.Kill(-p.Pid, s)
+}
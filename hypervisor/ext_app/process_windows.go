@@ -0,0 +1,35 @@
+package ext_app
+
+import (
+	"errors"
+	"os"
+	"os/exec"
+	"strings"
+	"syscall"
+)
+
+//windows has no SIGTERM, so a graceful stop can only kill
+var terminateSignal os.Signal = os.Kill
+
+func ParseSignal(s string) (os.Signal, error) {
+	switch strings.TrimPrefix(strings.ToUpper(s), "SIG") {
+	case "KILL", "9":
+		return os.Kill, nil
+	}
+
+	return nil, errors.New("Only KILL is supported on windows")
+}
+
+func setProcessGroup(cmd *exec.Cmd) {
+	cmd.SysProcAttr = &syscall.SysProcAttr{
+		CreationFlags: syscall.CREATE_NEW_PROCESS_GROUP}
+}
+
+func signalGroup(p *os.Process, sig os.Signal) error {
+	if sig != os.Kill {
+		return errors.New("Only KILL is supported on windows")
+	}
+
+	//FIXME: this only kills the direct child ("cmd /C"), not its children
+	return p.Kill()
+}
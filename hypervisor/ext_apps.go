@@ -2,13 +2,24 @@ package hypervisor
 
 import (
 	"errors"
+	"sort"
 	"strconv"
+	"time"
 
+	"github.com/skycoin/viscript/config"
 	"github.com/skycoin/viscript/msg"
 )
 
 var GlobalRunningExternalApps RunningExternalApps
 
+//apps which were stopped (& left the list), till they've actually ended
+var endingExternalApps []endingExternalApp
+
+type endingExternalApp struct {
+	ea     msg.ExternalAppInterface
+	report func(status string)
+}
+
 type RunningExternalApps struct {
 	TaskMap map[msg.ExternalAppId]msg.ExternalAppInterface
 }
@@ -18,8 +29,38 @@ func initRunningExternalApps() {
 }
 
 func teardownRunningExternalApps() {
+	//ids are sequential, so descending ids are the reverse start order.
+	//(later apps may depend on earlier ones, e.g. clients on meshnet-node)
+	ids := make([]int, 0, len(GlobalRunningExternalApps.TaskMap))
+
+	for id := range GlobalRunningExternalApps.TaskMap {
+		ids = append(ids, int(id))
+	}
+
+	sort.Sort(sort.Reverse(sort.IntSlice(ids)))
+
+	//each one has ended before the next is stopped.  Stop() doesn't wait,
+	//but viscript exiting would take the pending SIGKILL with it.
+	//(nothing else is running by now, so this can block)
+	for _, id := range ids {
+		ea := GlobalRunningExternalApps.TaskMap[msg.ExternalAppId(id)]
+		println("Stopping external app", id, "-", ea.GetFullCommandLine())
+		ea.TearDown()
+
+		deadline := time.Now().Add(config.StopGracePeriod() + time.Second)
+
+		for ea.GetExitStatus() == "" && time.Now().Before(deadline) {
+			time.Sleep(50 * time.Millisecond)
+		}
+
+		if status := ea.GetExitStatus(); status != "" {
+			println("External app", id, "ended:", status)
+		} else {
+			println("External app", id, "is still running after SIGKILL")
+		}
+	}
+
 	GlobalRunningExternalApps.TaskMap = nil
-	// TODO: Further cleanup
 }
 
 func ExternalAppIsRunning(id msg.ExternalAppId) bool {
@@ -49,6 +90,28 @@ func GetExternalApp(id msg.ExternalAppId) (msg.ExternalAppInterface, error) {
 	return nil, err
 }
 
+//apps which ended stay in the list (till killed), so
+//those which ended badly are counted as crashed
+func CountExternalApps() (running, crashed int) {
+	for _, ea := range GlobalRunningExternalApps.TaskMap {
+		switch status := ea.GetExitStatus(); status {
+		case "":
+			running++
+		case "exit status 0":
+		default:
+			crashed++
+		}
+	}
+
+	return
+}
+
+//stopping doesn't wait for the app, so report is called later (by
+//TickExternalApps), with how it ended, once it's been reaped
+func ReportWhenEnded(ea msg.ExternalAppInterface, report func(status string)) {
+	endingExternalApps = append(endingExternalApps, endingExternalApp{ea, report})
+}
+
 func RemoveExternalApp(id msg.ExternalAppId) {
 	delete(GlobalRunningExternalApps.TaskMap, id)
 }
@@ -78,4 +141,15 @@ func TickExternalApps() {
 	// p.Tick()
 	// }
 
+	stillEnding := endingExternalApps[:0]
+
+	for _, e := range endingExternalApps {
+		if status := e.ea.GetExitStatus(); status != "" {
+			e.report(status)
+		} else {
+			stillEnding = append(stillEnding, e)
+		}
+	}
+
+	endingExternalApps = stillEnding
 }
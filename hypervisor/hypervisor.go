@@ -35,6 +35,8 @@ Can objects create multiple channels?
 */
 
 import (
+	"os"
+
 	"github.com/skycoin/viscript/hypervisor/dbus"
 )
 
@@ -47,10 +49,25 @@ func Init() {
 	DbusGlobal.Init()
 }
 
+//the order matters: apps may still print while being stopped,
+//so the channels they talk through are closed only afterwards
 func Teardown() {
 	println("<hypervisor>.Teardown()")
+	teardownRunningExternalApps() //newest first
 	teardownTasks()
-	teardownRunningExternalApps()
-	DbusGlobal.PubsubChannels = nil
-	DbusGlobal.Resources = nil
+	DbusGlobal.Teardown()
+	flushLogs()
+}
+
+//
+//
+//private
+//
+//
+
+func flushLogs() {
+	//println() writes to stderr, fmt.Print*() to stdout.  either may
+	//be redirected to a file that we want complete after exiting
+	os.Stdout.Sync()
+	os.Stderr.Sync()
 }
@@ -1,6 +1,8 @@
 package mouse
 
 import (
+	"time"
+
 	"github.com/skycoin/viscript/app"
 )
 
@@ -10,14 +12,19 @@ var (
 	PixelDelta          app.Vec2F //used when determining new scrollbar position (in old text editor)
 	DeltaSinceLeftClick app.Vec2F
 	LeftButtonIsDown    bool
+	ClickCount          int //of the latest left press.  2 for a double click, 3 for triple, etc
 
 	// private
 	pixelSize_    app.Vec2F
 	prevPixelPos  app.Vec2F
 	canvasExtents app.Vec2F
 	nearThresh    float32 = 0.05 //nearness threshold (how close pointer should be to the edge)
+	lastClickAt   time.Time
+	lastClickPos  app.Vec2F
 )
 
+const multiClickTime = 400 * time.Millisecond //longest gap between clicks of a double (or triple) click
+
 func Update(pixelPos app.Vec2F) {
 	PrevGlPos = GlPos
 	setGlPosFrom(pixelPos)
@@ -26,6 +33,31 @@ func Update(pixelPos app.Vec2F) {
 	prevPixelPos.SetTo(pixelPos)
 }
 
+//counts clicks made quickly in the same spot (see ClickCount)
+func OnLeftPress() {
+	d := GlPos.GetDeltaFrom(lastClickPos)
+
+	if time.Since(lastClickAt) < multiClickTime &&
+		d.X < nearThresh && d.X > -nearThresh &&
+		d.Y < nearThresh && d.Y > -nearThresh {
+
+		ClickCount++
+	} else {
+		ClickCount = 1
+	}
+
+	lastClickAt = time.Now()
+	lastClickPos = GlPos
+}
+
+func NearLeft(bounds *app.Rectangle) bool {
+	return GlPos.X >= bounds.Left &&
+		GlPos.X <= bounds.Left+nearThresh &&
+		//also needs to be inside terminal
+		GlPos.Y <= bounds.Top &&
+		GlPos.Y >= bounds.Bottom
+}
+
 func NearRight(bounds *app.Rectangle) bool {
 	return GlPos.X <= bounds.Right &&
 		GlPos.X >= bounds.Right-nearThresh &&
@@ -34,6 +66,14 @@ func NearRight(bounds *app.Rectangle) bool {
 		GlPos.Y >= bounds.Bottom
 }
 
+func NearTop(bounds *app.Rectangle) bool {
+	return GlPos.Y <= bounds.Top &&
+		GlPos.Y >= bounds.Top-nearThresh &&
+		//also needs to be inside terminal
+		GlPos.X >= bounds.Left &&
+		GlPos.X <= bounds.Right
+}
+
 func NearBottom(bounds *app.Rectangle) bool {
 	return GlPos.Y >= bounds.Bottom &&
 		GlPos.Y <= bounds.Bottom+nearThresh &&
@@ -0,0 +1,72 @@
+package procfs
+
+/*
+	Reads resource usage of external apps straight from the OS,
+	so it also works for apps that don't import "viscript/signal".
+
+	Only Linux (/proc) is supported atm.  On other platforms every
+	call returns ErrUnsupported.
+*/
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+var ErrUnsupported = errors.New("procfs: process stats are only available on linux")
+
+type Usage struct {
+	Pid        int
+	NumProcs   int  //processes summed up (1 unless read as a tree)
+	State      byte //R, S, D, Z, T etc. (of the root process)
+	UserTime   time.Duration
+	SystemTime time.Duration
+	RSS        uint64 //resident set size in bytes
+	Threads    int
+	OpenFds    int
+	ReadBytes  uint64 //bytes actually fetched from storage
+	WriteBytes uint64 //bytes actually sent to storage
+}
+
+func (u *Usage) CPUTime() time.Duration {
+	return u.UserTime + u.SystemTime
+}
+
+func (u *Usage) String() string {
+	return fmt.Sprintf("Pid:%d Procs:%d State:%c CPU:%s (user %s, sys %s) "+
+		"RSS:%s Threads:%d Fds:%d IO read:%s write:%s",
+		u.Pid, u.NumProcs, u.State,
+		u.CPUTime(), u.UserTime, u.SystemTime,
+		FormatBytes(u.RSS), u.Threads, u.OpenFds,
+		FormatBytes(u.ReadBytes), FormatBytes(u.WriteBytes))
+}
+
+//sums up another process's usage (used when walking a process tree)
+func (u *Usage) add(o Usage) {
+	u.NumProcs += o.NumProcs
+	u.UserTime += o.UserTime
+	u.SystemTime += o.SystemTime
+	u.RSS += o.RSS
+	u.Threads += o.Threads
+	u.OpenFds += o.OpenFds
+	u.ReadBytes += o.ReadBytes
+	u.WriteBytes += o.WriteBytes
+}
+
+func FormatBytes(n uint64) string {
+	const unit = 1024
+
+	if n < unit {
+		return fmt.Sprintf("%dB", n)
+	}
+
+	div, exp := uint64(unit), 0
+
+	for x := n / unit; x >= unit; x /= unit {
+		div *= unit
+		exp++
+	}
+
+	return fmt.Sprintf("%.1f%ciB", float64(n)/float64(div), "KMGTPE"[exp])
+}
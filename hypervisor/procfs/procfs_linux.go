@@ -0,0 +1,234 @@
+//go:build linux
+// +build linux
+
+package procfs
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	procRoot = "/proc"
+
+	//USER_HZ.  it's 100 on practically every linux box,
+	//and reading the real value would need cgo (sysconf)
+	clockTicks = 100
+)
+
+//whether a process with this pid exists (zombies count as gone)
+func Alive(pid int) bool {
+	u, err := readStat(pid)
+	return err == nil && u.State != 'Z' && u.State != 'X'
+}
+
+//usage of a single process
+func Read(pid int) (Usage, error) {
+	u, err := readStat(pid)
+	if err != nil {
+		return u, err
+	}
+
+	//status & io are best effort (io is often unreadable without privileges)
+	readStatus(pid, &u)
+	readIO(pid, &u)
+	u.OpenFds = countFds(pid)
+
+	return u, nil
+}
+
+//usage of a process and all of its descendants summed up
+func ReadTree(pid int) (Usage, error) {
+	total, err := Read(pid)
+	if err != nil {
+		return total, err
+	}
+
+	for _, child := range descendantsOf(pid) {
+		u, err := Read(child)
+		if err != nil { //it exited while we were looking
+			continue
+		}
+
+		total.add(u)
+	}
+
+	return total, nil
+}
+
+//
+//
+//private
+//
+//
+
+func procPath(pid int, name string) string {
+	return filepath.Join(procRoot, strconv.Itoa(pid), name)
+}
+
+func readStat(pid int) (Usage, error) {
+	data, err := ioutil.ReadFile(procPath(pid, "stat"))
+	if err != nil {
+		return Usage{Pid: pid}, err
+	}
+
+	u, _, err := parseStat(data)
+	return u, err
+}
+
+//returns the usage found in /proc/<pid>/stat & the parent pid
+func parseStat(data []byte) (u Usage, ppid int, err error) {
+	//the command name (2nd field) is in parens & can contain spaces or parens
+	//itself, so everything is counted from the LAST closing paren
+	open := bytes.IndexByte(data, '(')
+	shut := bytes.LastIndexByte(data, ')')
+	if open < 0 || shut < open {
+		return u, 0, errors.New("procfs: malformed stat")
+	}
+
+	u.Pid, err = strconv.Atoi(strings.TrimSpace(string(data[:open])))
+	if err != nil {
+		return u, 0, err
+	}
+
+	//fields[0] is field #3 (state) in proc(5)
+	fields := strings.Fields(string(data[shut+1:]))
+	if len(fields) < 22 {
+		return u, 0, fmt.Errorf("procfs: stat has only %d fields", len(fields)+2)
+	}
+
+	u.NumProcs = 1
+	u.State = fields[0][0]
+	ppid, _ = strconv.Atoi(fields[1])
+	u.UserTime = ticksToDuration(fields[11])
+	u.SystemTime = ticksToDuration(fields[12])
+	u.Threads, _ = strconv.Atoi(fields[17])
+	rssPages, _ := strconv.ParseUint(fields[21], 10, 64)
+	u.RSS = rssPages * uint64(os.Getpagesize())
+
+	return u, ppid, nil
+}
+
+func ticksToDuration(s string) time.Duration {
+	ticks, _ := strconv.ParseUint(s, 10, 64)
+	return time.Duration(ticks) * time.Second / clockTicks
+}
+
+//status is more precise than stat for memory, so prefer it when readable
+func readStatus(pid int, u *Usage) {
+	f, err := os.Open(procPath(pid, "status"))
+	if err != nil {
+		return
+	}
+
+	defer f.Close()
+	parseStatus(f, u)
+}
+
+func parseStatus(r io.Reader, u *Usage) {
+	scanner := bufio.NewScanner(r)
+
+	for scanner.Scan() {
+		key, value := splitKeyValue(scanner.Text())
+
+		switch key {
+		case "VmRSS":
+			//e.g. "  1234 kB"
+			kb, err := strconv.ParseUint(strings.TrimSuffix(value, " kB"), 10, 64)
+			if err == nil {
+				u.RSS = kb * 1024
+			}
+		case "Threads":
+			if n, err := strconv.Atoi(value); err == nil {
+				u.Threads = n
+			}
+		}
+	}
+}
+
+func readIO(pid int, u *Usage) {
+	data, err := ioutil.ReadFile(procPath(pid, "io"))
+	if err != nil {
+		return
+	}
+
+	for _, line := range strings.Split(string(data), "\n") {
+		key, value := splitKeyValue(line)
+
+		switch key {
+		case "read_bytes":
+			u.ReadBytes, _ = strconv.ParseUint(value, 10, 64)
+		case "write_bytes":
+			u.WriteBytes, _ = strconv.ParseUint(value, 10, 64)
+		}
+	}
+}
+
+func splitKeyValue(line string) (string, string) {
+	i := strings.IndexByte(line, ':')
+	if i < 0 {
+		return "", ""
+	}
+
+	return line[:i], strings.TrimSpace(line[i+1:])
+}
+
+func countFds(pid int) int {
+	entries, err := ioutil.ReadDir(procPath(pid, "fd"))
+	if err != nil {
+		return 0
+	}
+
+	return len(entries)
+}
+
+//every process below pid (children, grandchildren etc.), found by
+//scanning all of /proc once, since .../task/*/children isn't always compiled in
+func descendantsOf(pid int) []int {
+	entries, err := ioutil.ReadDir(procRoot)
+	if err != nil {
+		return nil
+	}
+
+	children := make(map[int][]int) //parent pid to child pids
+
+	for _, e := range entries {
+		p, err := strconv.Atoi(e.Name())
+		if err != nil {
+			continue
+		}
+
+		data, err := ioutil.ReadFile(procPath(p, "stat"))
+		if err != nil {
+			continue
+		}
+
+		_, ppid, err := parseStat(data)
+		if err != nil {
+			continue
+		}
+
+		children[ppid] = append(children[ppid], p)
+	}
+
+	var found []int
+	queue := children[pid]
+
+	for len(queue) > 0 {
+		p := queue[0]
+		queue = queue[1:]
+		found = append(found, p)
+		queue = append(queue, children[p]...)
+	}
+
+	return found
+}
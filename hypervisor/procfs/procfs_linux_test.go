@@ -0,0 +1,56 @@
+package procfs
+
+import (
+	"os"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestParseStat(t *testing.T) {
+	//command name with a space and a paren, to make sure fields are counted from the last ')'
+	line := "4242 (meshnet (node)) S 4200 4242 4200 0 -1 4194560 1203 0 0 0 " +
+		"250 130 0 0 20 0 7 0 1234567 123456789 300 18446744073709551615 " +
+		"1 1 0 0 0 0 0 0 0 0 0 0 17 3 0 0 0 0 0"
+
+	u, ppid, err := parseStat([]byte(line))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.Pid != 4242 || ppid != 4200 || u.State != 'S' {
+		t.Fatalf("pid %d, ppid %d, state %c", u.Pid, ppid, u.State)
+	}
+
+	if u.UserTime != 2500*time.Millisecond || u.SystemTime != 1300*time.Millisecond {
+		t.Fatalf("user %s, sys %s", u.UserTime, u.SystemTime)
+	}
+
+	if u.Threads != 7 {
+		t.Fatalf("threads %d", u.Threads)
+	}
+
+	if u.RSS != 300*uint64(os.Getpagesize()) {
+		t.Fatalf("rss %d", u.RSS)
+	}
+}
+
+func TestParseStatus(t *testing.T) {
+	var u Usage
+	parseStatus(strings.NewReader("Name:\tnode\nThreads:\t12\nVmRSS:\t    2048 kB\n"), &u)
+
+	if u.RSS != 2048*1024 || u.Threads != 12 {
+		t.Fatalf("rss %d, threads %d", u.RSS, u.Threads)
+	}
+}
+
+func TestReadTreeOfSelf(t *testing.T) {
+	u, err := ReadTree(os.Getpid())
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if u.NumProcs < 1 || u.Threads < 1 || u.RSS == 0 || u.OpenFds == 0 {
+		t.Fatalf("implausible usage of own process: %s", u.String())
+	}
+}
@@ -0,0 +1,16 @@
+//go:build !linux
+// +build !linux
+
+package procfs
+
+func Alive(pid int) bool {
+	return false
+}
+
+func Read(pid int) (Usage, error) {
+	return Usage{Pid: pid}, ErrUnsupported
+}
+
+func ReadTree(pid int) (Usage, error) {
+	return Usage{Pid: pid}, ErrUnsupported
+}
@@ -0,0 +1,325 @@
+package stack
+
+/*
+	Starts the apps of a configured stack in dependency order,
+	waiting for each one to be ready before starting the ones
+	that depend on it.  Stopping goes in reverse order.
+
+	Starting takes a while, so it's advanced a step at a time by
+	Tick() (called every frame), which never blocks the main loop.
+
+	Stack apps are ordinary (detached) external apps, so "list_apps",
+	"attach", "kill" etc. work on them as usual.
+*/
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"time"
+
+	"github.com/skycoin/viscript/app"
+	"github.com/skycoin/viscript/config"
+	"github.com/skycoin/viscript/hypervisor"
+	"github.com/skycoin/viscript/hypervisor/ext_app"
+	"github.com/skycoin/viscript/msg"
+	"github.com/skycoin/viscript/signal"
+)
+
+const (
+	path = "hypervisor/stack/stack"
+
+	pollInterval = 100 * time.Millisecond
+)
+
+var (
+	running  = make(map[string][]msg.ExternalAppId) //stack name to its apps, in the order they were started
+	starting = make(map[string]*bringUp)            //(those of running which aren't up yet)
+)
+
+//progress gets reported line by line, e.g. to the terminal that asked
+type Reporter func(line string)
+
+//(while starting too)
+func IsRunning(name string) bool {
+	_, exists := running[name]
+	return exists
+}
+
+func IsStarting(name string) bool {
+	_, exists := starting[name]
+	return exists
+}
+
+func RunningNames() []string {
+	names := make([]string, 0, len(running))
+
+	for name := range running {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+//only returns errors found before anything is started.  how it
+//went later is passed to done (once Tick() got it up, or didn't).
+//if any app fails to start or become ready, the ones already
+//started are stopped again
+func Start(name string, report Reporter, done func(err error)) error {
+	app.At(path, "Start")
+
+	if IsRunning(name) {
+		return errors.New("Stack \"" + name + "\" is already running")
+	}
+
+	stack, exists := config.Global.Stacks[name]
+	if !exists {
+		return errors.New("Stack with name '" + name + "' doesn't exist. " +
+			"Try running 'stacks'.")
+	}
+
+	order, err := stack.StartOrder()
+	if err != nil {
+		return errors.New("Stack \"" + name + "\": " + err.Error())
+	}
+
+	running[name] = []msg.ExternalAppId{}
+	starting[name] = &bringUp{name: name, order: order, report: report, done: done}
+	return nil
+}
+
+//advances the stacks being started
+func Tick() {
+	for _, b := range starting {
+		b.tick()
+	}
+}
+
+//(also cancels starting it)
+func Stop(name string, report Reporter) error {
+	app.At(path, "Stop")
+
+	ids, exists := running[name]
+	if !exists {
+		return errors.New("Stack \"" + name + "\" isn't running")
+	}
+
+	if b, exists := starting[name]; exists {
+		delete(starting, name)
+		b.done(errors.New("Stack \"" + name + "\" was stopped before it was up"))
+	}
+
+	for i := len(ids) - 1; i >= 0; i-- {
+		ea, err := hypervisor.GetExternalApp(ids[i])
+		if err != nil { //already stopped some other way
+			continue
+		}
+
+		id := ids[i]
+		status := ea.Stop()
+		ea.TearDown()
+		hypervisor.RemoveExternalApp(id)
+		report(fmt.Sprintf("App %d %s", id, status))
+
+		hypervisor.ReportWhenEnded(ea, func(status string) {
+			report(fmt.Sprintf("App %d ended: %s", id, status))
+		})
+	}
+
+	delete(running, name)
+	return nil
+}
+
+//
+//
+//private
+//
+//
+
+//a stack being started, one app at a time
+type bringUp struct {
+	name   string
+	order  []config.StackApp
+	report Reporter
+	done   func(err error)
+
+	next      int                  //index (in order) of the app to start, or being waited for
+	ea        *ext_app.ExternalApp //being waited for (nil till it's started)
+	deadline  time.Time            //for it to be ready
+	output    *regexp.Regexp       //it still has to print (nil when none, or already seen)
+	checking  chan bool            //result of the other checks, while they're in flight
+	nextCheck time.Time            //(they're run every pollInterval)
+}
+
+func (b *bringUp) tick() {
+	if b.next == len(b.order) {
+		delete(starting, b.name)
+		b.done(nil)
+		return
+	}
+
+	sa := b.order[b.next]
+	var ready bool
+	var err error
+
+	if b.ea == nil {
+		err = b.startNext()
+	} else {
+		ready, err = b.pollReady()
+	}
+
+	if err != nil {
+		delete(starting, b.name)
+		b.report(sa.Name + ": " + err.Error())
+		b.report("Stopping what was started of \"" + b.name + "\"")
+		Stop(b.name, b.report)
+		b.done(errors.New("Stack \"" + b.name + "\" didn't come up"))
+		return
+	}
+
+	if ready {
+		b.report(sa.Name + " is ready")
+		b.ea = nil
+		b.next++
+	}
+}
+
+func (b *bringUp) startNext() error {
+	sa := b.order[b.next]
+
+	ea, err := startApp(sa)
+	if err != nil {
+		return err
+	}
+
+	running[b.name] = append(running[b.name], ea.Id)
+	b.report(fmt.Sprintf("Started %s (ID: %d), waiting until ready...", sa.Name, ea.Id))
+
+	b.ea = ea
+	b.deadline = time.Now().Add(sa.Ready.TimeoutOrDefault())
+	b.output = nil
+	b.checking = nil
+	b.nextCheck = time.Time{}
+
+	if sa.Ready.Output != "" {
+		//the regex was already checked by StartOrder()
+		b.output = regexp.MustCompile(sa.Ready.Output)
+	}
+
+	return nil
+}
+
+//(false with no error while it's still coming up)
+func (b *bringUp) pollReady() (bool, error) {
+	rc := b.order[b.next].Ready
+
+	if status := b.ea.GetExitStatus(); status != "" {
+		return false, errors.New("Ended before it was ready: " + status)
+	}
+
+	if time.Now().After(b.deadline) {
+		if b.output != nil {
+			return false, fmt.Errorf("Nothing matching \"%s\" printed within %s",
+				b.output.String(), rc.TimeoutOrDefault())
+		}
+
+		return false, fmt.Errorf("Not ready within %s", rc.TimeoutOrDefault())
+	}
+
+	if b.output != nil {
+		seen, err := b.ea.PollOutput(b.output)
+		if err != nil || !seen {
+			return false, err
+		}
+
+		b.output = nil
+	}
+
+	if b.checking == nil {
+		if time.Now().Before(b.nextCheck) {
+			return false, nil
+		}
+
+		checking := make(chan bool, 1) //(so it can finish after an abort)
+		b.checking = checking
+
+		go func(id msg.ExternalAppId) {
+			checking <- isReady(id, rc)
+		}(b.ea.Id)
+
+		return false, nil
+	}
+
+	select {
+	case ready := <-b.checking:
+		b.checking = nil
+		b.nextCheck = time.Now().Add(pollInterval)
+		return ready, nil
+	default:
+		return false, nil
+	}
+}
+
+func startApp(sa config.StackApp) (*ext_app.ExternalApp, error) {
+	tokens, err := config.GetTokensForApp(sa.AppName(), sa.Args)
+	if err != nil {
+		return nil, err
+	}
+
+	ea, err := ext_app.MakeNewExternalApp(sa.AppName(), tokens, true)
+	if err != nil {
+		return nil, err
+	}
+
+	err = ea.Start()
+	if err != nil {
+		return nil, err
+	}
+
+	hypervisor.AddExternalApp(ea.GetExternalAppInterface())
+	return ea, nil
+}
+
+//the checks which need the network (output is polled for separately).
+//they can take a while, so they're run in a goroutine
+func isReady(id msg.ExternalAppId, rc config.ReadyCheck) bool {
+	if rc.TCP != "" {
+		conn, err := net.DialTimeout("tcp", rc.TCP, pollInterval)
+		if err != nil {
+			return false
+		}
+
+		conn.Close()
+	}
+
+	if rc.Ping && !answersPing(id) {
+		return false
+	}
+
+	return true
+}
+
+func answersPing(id msg.ExternalAppId) bool {
+	client, ok := signal.GetClient(uint(id))
+	if !ok { //hasn't connected (yet)
+		return false
+	}
+
+	//Ping() has no timeout of its own
+	answered := make(chan bool, 1)
+
+	go func() {
+		_, err := client.Ping()
+		answered <- err == nil
+	}()
+
+	select {
+	case ok = <-answered:
+		return ok
+	case <-time.After(time.Second):
+		return false
+	}
+}
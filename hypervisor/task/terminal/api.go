@@ -44,6 +44,27 @@ func (st *State) SendCommand(command string, args []string) {
 	st.publishToOut(m)
 }
 
+//how many visual rows the log is scrolled back, out of how many it can be
+//(for the scrollbar of our terminal)
+func (st *State) Backscroll() (amount, max int) {
+	max = len(st.Cli.VisualRows) - st.NumBackscrollRows()
+	if max < 0 {
+		max = 0
+	}
+
+	return st.Cli.BackscrollAmount, max
+}
+
+func (st *State) ScrollBackTo(amount int) {
+	st.Cli.AdjustBackscrollOffset(amount-st.Cli.BackscrollAmount, st)
+	st.makePageOfLog(st.VisualInfo)
+}
+
+//for the tab & taskbar button of our terminal (empty for none)
+func (st *State) SetTitle(title string) {
+	st.publishToOut(msg.Serialize(msg.TypeSetTitle, msg.MessageSetTitle{title}))
+}
+
 func (st *State) PrintLn(s string) {
 	st.printLnAndMAYBELogIt(s, true)
 }
@@ -2,6 +2,8 @@ package task
 
 import (
 	"fmt"
+	"runtime"
+	"sort"
 	"strconv"
 	"strings"
 
@@ -9,6 +11,8 @@ import (
 	"github.com/skycoin/viscript/config"
 	"github.com/skycoin/viscript/hypervisor"
 	extAppImport "github.com/skycoin/viscript/hypervisor/ext_app"
+	"github.com/skycoin/viscript/hypervisor/procfs"
+	"github.com/skycoin/viscript/hypervisor/stack"
 	"github.com/skycoin/viscript/msg"
 	"github.com/skycoin/viscript/signal"
 	"time"
@@ -16,28 +20,53 @@ import (
 
 const cp = "hypervisor/task/terminal/commands"
 
+var startTime = time.Now() //(for sys_info's uptime)
+
 func (st *State) commandHelp() {
 	st.PrintLn("<bar>")
 	//st.PrintLn("Current commands:")
 	st.PrintLn("------ Terminals ------")
 	st.PrintLn("clear:                 Clears currently focused terminal.")
-	st.PrintLn("close_term <id>:       Close terminal by id.")
-	st.PrintLn("list_terms:            List all terminal ids.")
-	st.PrintLn("focus <id>:            Changes terminal & input focus.")
+	st.PrintLn("close_term <id>:       Close terminal by id (or name).")
+	st.PrintLn("list_terms:            List all terminal ids (with names & titles).")
+	st.PrintLn("focus <id>:            Changes terminal & input focus (by id or name).")
+	st.PrintLn("rename_term [id] <name>:")
+	st.PrintLn("                       Name this terminal (or another), to use in place of its id.")
+	st.PrintLn("layout [name]:         Arrange terminals: floating, grid, master, split_h")
+	st.PrintLn("                       (side by side) or split_v (on top of each other).")
 	st.PrintLn("defocus:               Defocus the current terminal.")
+	st.PrintLn("raise/lower [id]:      Bring a terminal's window to the front, or send it back.")
+	st.PrintLn("always_on_top [id]:    Toggle keeping a terminal's window above the others.")
 	st.PrintLn("move_term:             Move/offset terminal by given X & Y values")
 	st.PrintLn("new_term:              Add new terminal.")
+	st.PrintLn("new_tab:               Add new tab (with its own task) to this terminal's window.")
+	st.PrintLn("workspace [name]:      Show workspaces, or switch to (& maybe make) one.")
+	st.PrintLn("move_to_workspace <name>:")
+	st.PrintLn("                       Move this terminal's window to a workspace.")
+	st.PrintLn("split [h|v]:           Split terminal into panes, each with its own task:")
+	st.PrintLn("                       h (side by side, the default) or v (on top of each other).")
 	st.PrintLn("------ Apps -----------")
 	st.PrintLn("apps:                  Display all available apps with descriptions.")
 	st.PrintLn("attach    <id>:        Attach external app with given terminal id.")
+	st.PrintLn("kill <id> [signal]:    Stop app gracefully, or send it a signal (e.g. HUP).")
 	st.PrintLn("list_apps (-f):        List running apps (-f for full commands).")
-	st.PrintLn("ping      <id>:        Ping app with given id.")
-	st.PrintLn("res_usage <id>:        See resource usage for app with given id.")
+	st.PrintLn("list_clients:          List apps connected over \"viscript/signal\".")
+	st.PrintLn("ping      <id>:        Ping app with given id (or check its process).")
+	st.PrintLn("res_usage <id>:        See resource usage (CPU, memory, IO) of app with given id.")
 	st.PrintLn("shutdown  <id>:        [TODO] Shutdown external app with given id.")
-	st.PrintLn("start [-a] <command>:  Start external app. (-a to also attach).")
+	st.PrintLn("start [-a] <app>:      Start external app. (-a to also attach).")
+	st.PrintLn("                       Args can be positional or name=value.")
+	st.PrintLn("reload_config:         Re-read the config file (running apps keep going).")
+	st.PrintLn("sys_info:              Show viscript's own uptime, memory & goroutines.")
+	st.PrintLn("------ Stacks ---------")
+	st.PrintLn("stacks:                List configured app stacks.")
+	st.PrintLn("start_stack <name>:    Start apps of a stack, in dependency order.")
+	st.PrintLn("stop_stack  <name>:    Stop apps of a stack, in reverse order.")
 	// st.PrintLn("rpc:                   Issues command: \"go run rpc/cli/cli.go\"")
 	// st.PrintLn("Current hotkeys:")
+	st.PrintLn("CTRL+C:                Interrupt (SIGINT) currently attached app.")
 	st.PrintLn("CTRL+Z:                Detach currently attached app.")
+	st.PrintLn("                       (hotkeys are set in the \"keybindings:\" config section)")
 	// st.PrintLn("    CTRL+C:           ___description goes here___")
 	st.PrintLn("<bar>")
 }
@@ -84,7 +113,46 @@ func (st *State) commandAppHelp(args []string) {
 		return
 	}
 
-	st.PrintLn(config.Global.Apps[appName].Help)
+	appConf := config.Global.Apps[appName]
+
+	if usage := appConf.Usage(appName); usage != "" {
+		st.PrintLn(usage)
+	}
+
+	if appConf.Help != "" {
+		st.PrintLn(appConf.Help)
+	}
+}
+
+func (st *State) commandReloadConfig() {
+	app.At(cp, "commandReloadConfig")
+
+	diff, err := config.Reload()
+	st.printConfigReload(diff, err)
+}
+
+func (st *State) printConfigReload(diff config.Diff, err error) {
+	if err != nil {
+		st.PrintError("Config not reloaded (still using the previous one):")
+
+		//Problems are already 1 per line
+		for _, line := range strings.Split(err.Error(), "\n") {
+			st.PrintLn(line)
+		}
+
+		return
+	}
+
+	if diff.IsEmpty() {
+		st.PrintLn("Reloaded " + config.Path() + ", no apps changed.")
+		return
+	}
+
+	st.PrintLn("Reloaded " + config.Path() + ":")
+
+	for _, line := range diff.Lines() {
+		st.PrintLn("    " + line)
+	}
 }
 
 func (st *State) commandClearTerminal() {
@@ -114,18 +182,11 @@ func (st *State) commandStart(args []string) {
 		return
 	}
 
-	var tokens []string
-
-	//if there are user passed args for the app override defaults set in config
-	if len(args) > 1 {
-		pathToApp := config.GetPathForApp(appName)
-		tokens = append(tokens, pathToApp)
-
-		for _, arg := range args[1:] {
-			tokens = append(tokens, strings.ToLower(arg))
-		}
-	} else {
-		tokens = config.GetPathWithDefaultArgsForApp(appName)
+	tokens, err := config.GetTokensForApp(appName, args[1:])
+	if err != nil {
+		st.PrintError(err.Error())
+		st.PrintLn("Type \"help " + appName + "\" for expected parameters.")
+		return
 	}
 
 	//if the app is daemon not allow to attach to it
@@ -133,7 +194,7 @@ func (st *State) commandStart(args []string) {
 		detached = true
 	}
 
-	newExternalApp, err := extAppImport.MakeNewExternalApp(tokens, detached)
+	newExternalApp, err := extAppImport.MakeNewExternalApp(appName, tokens, detached)
 	if err != nil {
 		st.PrintError(err.Error())
 		return
@@ -160,6 +221,80 @@ func (st *State) commandStart(args []string) {
 		newExternalApp.CommandLine + ")")
 }
 
+func (st *State) commandStacks() {
+	names := config.StackNames()
+
+	if len(names) == 0 {
+		st.PrintLn("No stacks configured.")
+		return
+	}
+
+	for _, name := range names {
+		s := config.Global.Stacks[name]
+		status := "stopped"
+
+		if stack.IsStarting(name) {
+			status = "starting"
+		} else if stack.IsRunning(name) {
+			status = "running"
+		}
+
+		st.PrintLn(fmt.Sprintf("%s [%s] -%s", name, status, s.Desc))
+
+		order, err := s.StartOrder()
+		if err != nil {
+			st.PrintError("    " + err.Error())
+			continue
+		}
+
+		for _, sa := range order {
+			st.PrintLn("    " + sa.Name + " (" + sa.AppName() + ")")
+		}
+	}
+}
+
+func (st *State) commandStartStack(args []string) {
+	app.At(cp, "commandStartStack")
+
+	if len(args) < 1 {
+		st.PrintError("No stack name passed! e.g. start_stack meshnet")
+		return
+	}
+
+	name := args[0]
+
+	//progress shows up as the apps come up (see stack.Tick)
+	err := stack.Start(name, st.PrintLn, func(err error) {
+		if err != nil {
+			st.PrintError(err.Error())
+			return
+		}
+
+		st.PrintLn("Stack \"" + name + "\" is up.")
+	})
+
+	if err != nil {
+		st.PrintError(err.Error())
+	}
+}
+
+func (st *State) commandStopStack(args []string) {
+	app.At(cp, "commandStopStack")
+
+	if len(args) < 1 {
+		st.PrintError("No stack name passed! e.g. stop_stack meshnet")
+		return
+	}
+
+	err := stack.Stop(args[0], st.PrintLn)
+	if err != nil {
+		st.PrintError(err.Error())
+		return
+	}
+
+	st.PrintLn("Stack \"" + args[0] + "\" is down.")
+}
+
 func (st *State) commandAppPing(args []string) {
 	app.At(cp, "commandAppPing")
 
@@ -176,7 +311,8 @@ func (st *State) commandAppPing(args []string) {
 
 	client, ok := signal.GetClient(uint(passedID))
 	if !ok {
-		st.PrintError("Task with given id is not running.")
+		//no signal client, so the best we can do is ask the OS
+		st.pingProcess(msg.ExternalAppId(passedID))
 		return
 	}
 
@@ -189,6 +325,21 @@ func (st *State) commandAppPing(args []string) {
 	st.PrintLn(fmt.Sprintf("ping time %s", time.Now().Sub(start).String()))
 }
 
+func (st *State) pingProcess(id msg.ExternalAppId) {
+	pid, err := getProcessIdOfExternalApp(id)
+	if err != nil {
+		st.PrintError(err.Error())
+		return
+	}
+
+	if !procfs.Alive(pid) {
+		st.PrintError(fmt.Sprintf("Process %d of app %d is gone.", pid, id))
+		return
+	}
+
+	st.PrintLn(fmt.Sprintf("no signal client, but process %d is alive", pid))
+}
+
 func (st *State) commandShutDown(args []string) {
 	app.At(cp, "commandShutDown")
 
@@ -218,7 +369,65 @@ func (st *State) commandShutDown(args []string) {
 	st.PrintLn(fmt.Sprintf("shutdown pid %d", resp.Pid))
 }
 
+func (st *State) commandKill(args []string) {
+	app.At(cp, "commandKill")
+
+	if len(args) < 1 {
+		st.PrintError("No task id passed! e.g. kill 1 (or kill 1 HUP)")
+		return
+	}
+
+	passedID, err := strconv.Atoi(args[0])
+	if err != nil {
+		st.PrintError("Task id must be an integer.")
+		return
+	}
+
+	eaId := msg.ExternalAppId(passedID)
+
+	ea, err := hypervisor.GetExternalApp(eaId)
+	if err != nil {
+		st.PrintError(err.Error())
+		return
+	}
+
+	//just pass on a specific signal, the app decides what happens next
+	if len(args) > 1 {
+		sig, err := extAppImport.ParseSignal(args[1])
+		if err != nil {
+			st.PrintError(err.Error())
+			return
+		}
+
+		err = ea.Signal(sig)
+		if err != nil {
+			st.PrintError(err.Error())
+			return
+		}
+
+		st.PrintLn(fmt.Sprintf("Sent %s to app %d", sig.String(), passedID))
+		return
+	}
+
+	//(so it's reported once, not also as the attached app ending)
+	if st.task.HasExternalAppAttached() && st.task.attachedExternalApp.GetId() == eaId {
+		st.task.ExitExternalApp()
+		return
+	}
+
+	status := ea.Stop()
+	ea.TearDown()
+	hypervisor.RemoveExternalApp(eaId)
+	st.PrintLn(fmt.Sprintf("App %d %s", passedID, status))
+
+	hypervisor.ReportWhenEnded(ea, func(status string) {
+		st.PrintLn(fmt.Sprintf("App %d ended: %s", passedID, status))
+	})
+}
+
 func (st *State) commandResourceUsage(args []string) {
+	app.At(cp, "commandResourceUsage")
+
 	if len(args) < 1 {
 		st.PrintError("No task id passed! e.g. res_usage 1")
 		return
@@ -230,9 +439,24 @@ func (st *State) commandResourceUsage(args []string) {
 		return
 	}
 
+	//what the OS knows about the whole process tree (works for any app)
+	pid, pidErr := getProcessIdOfExternalApp(msg.ExternalAppId(passedID))
+	if pidErr == nil {
+		usage, err := procfs.ReadTree(pid)
+		if err != nil {
+			st.PrintError(err.Error())
+		} else {
+			st.PrintLn(usage.String())
+		}
+	}
+
+	//what the app itself reports (only if it imports "viscript/signal")
 	client, ok := signal.GetClient(uint(passedID))
 	if !ok {
-		st.PrintError("Task with given id is not running.")
+		if pidErr != nil {
+			st.PrintError(pidErr.Error())
+		}
+
 		return
 	}
 
@@ -292,7 +516,16 @@ func (st *State) commandListRunningExternalApps(args []string) {
 		fullPrint = true
 	}
 
-	for id, extApp := range extApps {
+	ids := []int{}
+
+	for id := range extApps {
+		ids = append(ids, int(id))
+	}
+
+	sort.Ints(ids)
+
+	for _, id := range ids {
+		extApp := extApps[msg.ExternalAppId(id)]
 		appCmd := ""
 
 		if fullPrint {
@@ -301,10 +534,52 @@ func (st *State) commandListRunningExternalApps(args []string) {
 			appCmd = strings.Split(extApp.GetFullCommandLine(), " ")[0]
 		}
 
-		st.Printf("[ %d ] -> [ %s ]\n", int(id), appCmd)
+		if status := extApp.GetExitStatus(); status != "" {
+			appCmd += " (ended: " + status + ")"
+		}
+
+		st.Printf("[ %d ] -> [ %s ]\n", id, appCmd)
 	}
 }
 
+func (st *State) commandListSignalClients() {
+	app.At(cp, "commandListSignalClients")
+
+	ids := signal.ClientIds()
+	if len(ids) == 0 {
+		st.PrintLn("No apps connected over \"viscript/signal\".")
+		return
+	}
+
+	for _, id := range ids {
+		appCmd := "?"
+
+		if ea, err := hypervisor.GetExternalApp(msg.ExternalAppId(id)); err == nil {
+			appCmd = ea.GetFullCommandLine()
+		}
+
+		st.Printf("[ %d ] -> [ %s ]\n", id, appCmd)
+	}
+}
+
+//about viscript itself (what res_usage shows for apps)
+func (st *State) commandSysInfo() {
+	app.At(cp, "commandSysInfo")
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	running, crashed := hypervisor.CountExternalApps()
+
+	st.PrintLn("Time:        " + time.Now().Format("2006-01-02 15:04:05"))
+	st.PrintLn("Uptime:      " + time.Since(startTime).Truncate(time.Second).String())
+	st.PrintLn(fmt.Sprintf("Goroutines:  %d", runtime.NumGoroutine()))
+	st.PrintLn("Memory:      " + procfs.FormatBytes(mem.Alloc) + " in use, " +
+		procfs.FormatBytes(mem.Sys) + " from the OS")
+	st.PrintLn(fmt.Sprintf("GC runs:     %d", mem.NumGC))
+	st.PrintLn(fmt.Sprintf("Apps:        %d running, %d crashed", running, crashed))
+	st.PrintLn(fmt.Sprintf("Clients:     %d", len(signal.ClientIds())))
+}
+
 func (st *State) commandCloseTerminal_FIRST_STAGE(args []string) {
 	if len(args) != 1 {
 		//args failure (too many/few passed)
@@ -320,7 +595,7 @@ func (st *State) commandCloseTerminal_FIRST_STAGE(args []string) {
 
 func (st *State) commandFocus_FIRST_STAGE(args []string) {
 	if len(args) < 1 {
-		st.PrintError("Must give me AT LEAST the 1st number of the id")
+		st.PrintError("Must give me AT LEAST the 1st number of the id (or the name)")
 		return
 	}
 
@@ -358,3 +633,23 @@ func (st *State) commandMoveTerminal(args []string) {
 	st.publishToOut(msg.Serialize(
 		msg.TypeMoveTerminal, msg.MessageMoveTerminal{int32(x), int32(y)}))
 }
+
+//
+//
+//private
+//
+//
+
+func getProcessIdOfExternalApp(id msg.ExternalAppId) (int, error) {
+	ea, err := hypervisor.GetExternalApp(id)
+	if err != nil {
+		return 0, err
+	}
+
+	pid := ea.GetProcessId()
+	if pid == 0 {
+		return 0, fmt.Errorf("External app with id %d has no process", id)
+	}
+
+	return pid, nil
+}
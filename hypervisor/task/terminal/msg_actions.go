@@ -2,8 +2,11 @@ package task
 
 import (
 	"fmt"
+	"os"
+	"strings"
 
 	//"github.com/skycoin/viscript/app"
+	"github.com/skycoin/viscript/config"
 	"github.com/skycoin/viscript/hypervisor"
 	"github.com/skycoin/viscript/msg"
 )
@@ -31,8 +34,7 @@ func (st *State) onKey(m msg.MessageKey, serializedMsg []byte) {
 	switch msg.Action(m.Action) {
 
 	case msg.Press: //one time, when key is first pressed
-		//modifier key combos should never auto-repeat?
-		st.onNONRepeatableKey(m)
+		//(key bindings, like ctrl+c, never get here.  see onTaskAction())
 		fallthrough
 		//^^^^^^^^^^
 	case msg.Repeat: //constantly repeated for as long as key is pressed
@@ -45,6 +47,29 @@ func (st *State) onKey(m msg.MessageKey, serializedMsg []byte) {
 	}
 }
 
+//goes in at the cursor as if it was typed.  while an app is attached,
+//each line break enters what's before it, sending it to the app.
+//otherwise they become spaces, so nothing runs till you press Enter
+func (st *State) onPaste(m msg.MessagePaste) {
+	enter := msg.Serialize(msg.TypeKey,
+		msg.MessageKey{Key: uint32(msg.KeyEnter), Action: uint8(msg.Press)})
+
+	for _, char := range strings.Replace(m.Text, "\r\n", "\n", -1) {
+		switch {
+
+		case char == '\n' && st.task.HasExternalAppAttached():
+			st.Cli.OnEnter(st, enter)
+
+		case char == '\n' || char == '\t':
+			st.Cli.InsertCharIfItFits(' ', st)
+
+		case char >= ' ':
+			st.Cli.InsertCharIfItFits(uint32(char), st)
+
+		}
+	}
+}
+
 func (st *State) onTerminalIds(m msg.MessageTerminalIds) {
 	st.storedTerminalIds = m.TermIds
 	num := len(m.TermIds)
@@ -58,9 +83,13 @@ func (st *State) onTerminalIds(m msg.MessageTerminalIds) {
 
 	st.PrintLn(str)
 
-	for _, termID := range m.TermIds {
+	for i, termID := range m.TermIds {
 		s := fmt.Sprintf("    %d", termID)
 
+		if i < len(m.Labels) && m.Labels[i] != fmt.Sprint(termID) {
+			s += "    " + m.Labels[i]
+		}
+
 		if termID == m.Focused {
 			st.PrintLn(s + "    (FOCUSED)")
 		} else {
@@ -71,29 +100,32 @@ func (st *State) onTerminalIds(m msg.MessageTerminalIds) {
 	st.Cli.EchoWholeCommand(st.task.OutChannelId)
 }
 
-func (st *State) onNONRepeatableKey(m msg.MessageKey) {
-	switch m.Key {
+//a key binding the viewport sent us (see config/keybindings.go)
+func (st *State) onTaskAction(m msg.MessageTaskAction) {
+	switch m.Action {
 
-	case msg.KeyC:
-		if m.Mod == msg.GLFW_MOD_CONTROL {
-			if !st.task.HasExternalAppAttached() {
-				return
-			}
+	case config.ActionClear:
+		st.commandClearTerminal()
 
-			//TODO: send the exit request i.e sigint or
-			//just maybe kill the task?
+	case config.ActionInterrupt:
+		if !st.task.HasExternalAppAttached() {
+			return
 		}
 
-	case msg.KeyZ:
-		if m.Mod == msg.GLFW_MOD_CONTROL {
-			if !st.task.HasExternalAppAttached() {
-				return
-			}
+		//like a shell would, interrupt the app (& anything it spawned)
+		err := st.task.attachedExternalApp.Signal(os.Interrupt)
+		if err != nil {
+			st.PrintError(err.Error())
+		}
 
-			st.PrintLn("Detaching external app")
-			st.task.DetachExternalApp()
+	case config.ActionDetach:
+		if !st.task.HasExternalAppAttached() {
+			return
 		}
 
+		st.PrintLn("Detaching external app")
+		st.task.DetachExternalApp()
+
 	}
 }
 
@@ -184,6 +216,12 @@ func (st *State) onUserCommand(cmd string, args []string) {
 	case "apps":
 		st.commandApps()
 
+	//stacking of the terminal's window (or the one of the id given)
+	case "aot":
+		fallthrough
+	case "always_on_top":
+		st.SendCommand("always_on_top", args)
+
 	//attach external app to terminal task
 	case "attach":
 		st.commandAttach(args)
@@ -209,11 +247,24 @@ func (st *State) onUserCommand(cmd string, args []string) {
 	case "focus":
 		st.commandFocus_FIRST_STAGE(args)
 
+	//arrange terminals
+	case "layout":
+		st.SendCommand("layout", args)
+
+	case "lower":
+		st.SendCommand("lower", args)
+
 	case "la":
 		fallthrough
 	case "list_apps":
 		st.commandListRunningExternalApps(args)
 
+	//list apps connected over signal
+	case "lc":
+		fallthrough
+	case "list_clients":
+		st.commandListSignalClients()
+
 	//list all terminals
 	case "lt":
 		fallthrough
@@ -222,6 +273,12 @@ func (st *State) onUserCommand(cmd string, args []string) {
 	case "list_terms":
 		st.SendCommand("list_terms", []string{})
 
+	//move terminal's window to another workspace
+	case "mtw":
+		fallthrough
+	case "move_to_workspace":
+		st.SendCommand("move_to_workspace", args)
+
 	//move terminal
 	case "mt":
 		fallthrough
@@ -234,10 +291,27 @@ func (st *State) onUserCommand(cmd string, args []string) {
 	case "new_term":
 		st.SendCommand("new_term", []string{})
 
+	//add new tab to the terminal's window
+	case "new_tab":
+		st.SendCommand("new_tab", []string{})
+
 	//ping app
 	case "ping":
 		st.commandAppPing(args)
 
+	case "raise":
+		st.SendCommand("raise", args)
+
+	//re-read config file
+	case "reload_config":
+		st.commandReloadConfig()
+
+	//name terminal (to use in place of its id)
+	case "rn":
+		fallthrough
+	case "rename_term":
+		st.SendCommand("rename_term", args)
+
 	//resource usage
 	case "ru":
 		fallthrough
@@ -249,18 +323,56 @@ func (st *State) onUserCommand(cmd string, args []string) {
 	case "rpc":
 		st.commandStart([]string{"-a", "go", "run", "rpc/cli/cli.go"})
 
+	//signal running app (terminates gracefully if no signal given)
+	case "k":
+		fallthrough
+	case "kill":
+		st.commandKill(args)
+
 	//shutdown running app
 	case "sd":
 		fallthrough
 	case "shutdown":
 		st.commandShutDown(args)
 
+	//split the focused terminal into panes
+	case "sp":
+		fallthrough
+	case "split":
+		st.SendCommand("split", args)
+
 	//start new external app, detached running in bg by default
 	case "s":
 		fallthrough
 	case "start":
 		st.commandStart(args)
 
+	//list configured stacks (& whether they're up)
+	case "stacks":
+		st.commandStacks()
+
+	//start apps of a stack in dependency order
+	case "ss":
+		fallthrough
+	case "start_stack":
+		st.commandStartStack(args)
+
+	//stop apps of a stack in reverse order
+	case "stop_stack":
+		st.commandStopStack(args)
+
+	//viscript's own time, uptime, memory & goroutines
+	case "si":
+		fallthrough
+	case "sys_info":
+		st.commandSysInfo()
+
+	//show/switch workspaces
+	case "ws":
+		fallthrough
+	case "workspace":
+		st.SendCommand("workspace", args)
+
 	default:
 		st.PrintError("\"" + cmd + "\" is an unknown command.")
 
@@ -29,6 +29,16 @@ func (st *State) UnpackMessage(msgType uint16, message []byte) []byte {
 		msg.MustDeserialize(message, &m)
 		st.makePageOfLog(m) //propogate Terminal changes to task
 
+	case msg.TypePaste:
+		var m msg.MessagePaste
+		msg.MustDeserialize(message, &m)
+		st.onPaste(m)
+
+	case msg.TypeTaskAction:
+		var m msg.MessageTaskAction
+		msg.MustDeserialize(message, &m)
+		st.onTaskAction(m)
+
 	case msg.TypeTerminalIds:
 		var m msg.MessageTerminalIds
 		msg.MustDeserialize(message, &m)
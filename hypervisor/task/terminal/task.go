@@ -1,13 +1,23 @@
 package task
 
 import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+
 	"github.com/skycoin/viscript/app"
+	"github.com/skycoin/viscript/config"
 	"github.com/skycoin/viscript/hypervisor"
 	"github.com/skycoin/viscript/msg"
 )
 
 var path = "hypervisor/task/terminal/task"
 
+//attached apps set the title of their terminal like they would an xterm's:
+//ESC ] 0 ; title BEL   (or ESC ] 2 ;, & ending in ESC \ instead of BEL)
+var titleSequence = regexp.MustCompile("\x1b\\][02];([^\x07\x1b]*)(\x07|\x1b\\\\)")
+
 type Task struct {
 	Id           msg.TaskId
 	Type         msg.TaskType
@@ -61,6 +71,7 @@ func (ta *Task) AttachExternalApp(eai msg.ExternalAppInterface) error {
 
 	ta.attachedExternalApp = eai
 	ta.hasExternalAppAttached = true
+	ta.State.SetTitle(appTitle(eai) + " (running)")
 
 	return nil
 }
@@ -70,15 +81,39 @@ func (ta *Task) DetachExternalApp() {
 	// ta.attachedExternalApp.Detach()
 	ta.attachedExternalApp = nil
 	ta.hasExternalAppAttached = false
+	ta.State.SetTitle("")
 }
 
 func (ta *Task) ExitExternalApp() {
 	app.At(path, "ExitExternalApp")
 	ta.hasExternalAppAttached = false
-	id := ta.attachedExternalApp.GetId() //for removing from global list.
-	ta.attachedExternalApp.TearDown()    //(and cleanup)
+	ta.State.SetTitle("")
+	ea := ta.attachedExternalApp
+	id := ea.GetId() //for removing from global list.
+	status := ea.Stop()
+	ea.TearDown() //(and cleanup)
 	ta.attachedExternalApp = nil
 	hypervisor.RemoveExternalApp(id) //...from GlobalRunningExternalApps.TaskMap
+	ta.State.PrintLn(fmt.Sprintf("External app %d %s", id, status))
+
+	hypervisor.ReportWhenEnded(ea, func(status string) {
+		ta.State.PrintLn(fmt.Sprintf("External app %d ended: %s", id, status))
+	})
+}
+
+//called every frame.  reloads the config file when it changed on disk
+//(if "watchConfig" is set), & shows what changed in every terminal
+func TickConfigWatcher() {
+	diff, reloaded, err := config.ReloadIfChanged()
+	if !reloaded && err == nil {
+		return
+	}
+
+	for _, t := range hypervisor.GlobalTasks.TaskMap {
+		if ta, ok := t.(*Task); ok {
+			ta.State.printConfigReload(diff, err)
+		}
+	}
 }
 
 //implement the interface
@@ -118,9 +153,38 @@ func (ta *Task) Tick() {
 	// 	//multiple goroutines at the same time to avoid any side effects
 	// 	ta.ExitExternalApp()
 	// }
-	case data := <-ta.attachedExternalApp.GetOutputChannel():
+	case data, ok := <-ta.attachedExternalApp.GetOutputChannel():
+		if !ok { //torn down elsewhere (e.g. "kill" from another terminal), & now ended
+			title := appTitle(ta.attachedExternalApp)
+			ta.State.PrintLn(fmt.Sprintf("Attached app %d ended: %s",
+				ta.attachedExternalApp.GetId(), ta.attachedExternalApp.GetExitStatus()))
+			ta.DetachExternalApp()
+			ta.State.SetTitle(title + " (ended)")
+			return
+		}
+
 		println("Received data from external app, sending to term.")
-		ta.State.PrintLn(string(data))
+		s := string(data)
+
+		for _, match := range titleSequence.FindAllStringSubmatch(s, -1) {
+			ta.State.SetTitle(match[1])
+		}
+
+		s = titleSequence.ReplaceAllString(s, "")
+
+		if s != "" {
+			ta.State.PrintLn(s)
+		}
 	default:
 	}
 }
+
+//name of the app's program, for the title of its terminal
+func appTitle(eai msg.ExternalAppInterface) string {
+	fields := strings.Fields(eai.GetFullCommandLine())
+	if len(fields) == 0 {
+		return "app"
+	}
+
+	return filepath.Base(fields[0])
+}
@@ -56,9 +56,6 @@ func initTasks() {
 }
 
 func teardownTasks() {
+	//external apps (which tasks may be attached to) are stopped before this
 	GlobalTasks.TaskMap = nil
-	//TODO: actually call teardown methods on all the tasks and also
-	//external apps. what about Alt+f4?
-	//upon application exit we need to terminate all the running tasks
-	//and external apps
 }
@@ -1,5 +1,9 @@
 package msg
 
+import (
+	"os"
+)
+
 const ChannelCapacity = 4096 // FIXME?  might only need capacity of 2?
 // .... onChar is always paired with an immediate onKey, making 2 entries at once
 
@@ -23,6 +27,10 @@ type ExternalAppInterface interface {
 	GetFullCommandLine() string
 	GetOutputChannel() chan []byte
 	GetExitChannel() chan struct{}
+	GetExitStatus() string //empty while still running
+	GetProcessId() int     //0 when not running
+	Signal(os.Signal) error
 	Start() error
+	Stop() string //graceful (SIGTERM, then SIGKILL), doesn't wait for it to end
 	TearDown()
 }
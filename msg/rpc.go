@@ -10,4 +10,6 @@ type TaskInfo struct {
 type TermAndTaskIds struct {
 	TerminalId     TerminalId
 	AttachedTaskId TaskId
+	Name           string
+	Title          string
 }
@@ -13,6 +13,9 @@ const (
 	TypeTerminalIds      = 8 + CATEGORY_Terminal
 	TypeVisualInfo       = 9 + CATEGORY_Terminal
 	TypeFrameBufferSize  = 10 + CATEGORY_Terminal //start of low level events
+	TypeTaskAction       = 11 + CATEGORY_Terminal //a key binding, for the focused terminal's task
+	TypeSetTitle         = 12 + CATEGORY_Terminal //task -> terminal
+	TypePaste            = 13 + CATEGORY_Terminal //from the clipboard, for the focused terminal's task
 )
 
 type MessageClear struct { //this type simply signals that we need a .clear() call in terminal
@@ -55,6 +58,7 @@ type MessageSetCursor struct {
 type MessageTerminalIds struct {
 	Focused TerminalId
 	TermIds []TerminalId
+	Labels  []string //(name & title, as in the tab) of each of TermIds
 }
 
 type MessageVisualInfo struct {
@@ -65,6 +69,18 @@ type MessageVisualInfo struct {
 	PromptRows uint32
 }
 
+type MessageTaskAction struct {
+	Action string //config.Action*
+}
+
+type MessageSetTitle struct { //shown in the tab & on the taskbar (empty for none)
+	Title string
+}
+
+type MessagePaste struct {
+	Text string
+}
+
 //low level events
 type MessageFrameBufferSize struct {
 	X uint32
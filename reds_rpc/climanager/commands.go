@@ -64,7 +64,7 @@ func (c *CliManager) ListTermIDsWithAttachedTasks(_ []string) error {
 	}
 
 	fmt.Printf("Terminals (%d) defaults marked with {}:\n", len(termsWithTaskIDs))
-	fmt.Println("\nIdx\tTerminal Id\t\tAttached Task Id")
+	fmt.Println("\nIdx\tTerminal Id\t\tAttached Task Id\tName & Title")
 
 	for index, term := range termsWithTaskIDs {
 		fmt.Printf("[ %d ]\t", index)
@@ -83,7 +83,7 @@ func (c *CliManager) ListTermIDsWithAttachedTasks(_ []string) error {
 			fmt.Printf("  %d\t", term.AttachedTaskId)
 		}
 
-		fmt.Printf("\n")
+		fmt.Printf("%s\t%s\n", term.Name, term.Title)
 	}
 
 	println()
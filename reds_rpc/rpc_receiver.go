@@ -23,13 +23,16 @@ func (receiver *RPCReceiver) ListTerminalIDsWithTaskIDs(_ []string, result *[]by
 		ids = append(ids,
 			msg.TermAndTaskIds{
 				TerminalId:     id,
-				AttachedTaskId: term.AttachedTask})
+				AttachedTaskId: term.AttachedTask,
+				Name:           term.Name,
+				Title:          term.Title})
 	}
 
 	println("[==============================]")
 	println("Terms with task IDs:")
 	for _, t := range ids {
-		println("Terminal ID:", t.TerminalId, "\tAttached Task ID:", t.AttachedTaskId)
+		println("Terminal ID:", t.TerminalId, "\tAttached Task ID:", t.AttachedTaskId,
+			"\tName:", t.Name, "\tTitle:", t.Title)
 	}
 
 	*result = msg.Serialize(uint16(0), ids)
@@ -105,7 +108,7 @@ func (receiver *RPCReceiver) GetTermChannelInfo(args []string, result *[]byte) e
 
 func (receiver *RPCReceiver) StartTerminalWithTask(_ []string, result *[]byte) error {
 	println("\nHandling Request: Start terminal with task")
-	terms := terminal.Terms
+	terms := &terminal.Terms
 	newTerminalID := terms.Add()
 	println("[==============================]")
 	fmt.Println("Terminal with ID", newTerminalID, "created")
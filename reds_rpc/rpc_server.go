@@ -5,12 +5,11 @@ import (
 	"net"
 	"net/http"
 	"net/rpc"
-	"os"
 )
 
-const DefaultPort = "7777"
-
 type RPC struct {
+	listener net.Listener
+	closing  bool
 }
 
 func NewRPC() *RPC {
@@ -18,13 +17,8 @@ func NewRPC() *RPC {
 	return newRPC
 }
 
-func (r *RPC) Serve() {
-	port := os.Getenv("TERMINAL_RPC_PORT")
-	if port == "" {
-		log.Println("No TERMINAL_RPC_PORT environmental variable is found, assigning default port value:", DefaultPort)
-		port = DefaultPort
-	}
-
+//addr is host:port, or just :port for all interfaces
+func (r *RPC) Serve(addr string) {
 	receiver := new(RPCReceiver)
 	err := rpc.Register(receiver)
 	if err != nil {
@@ -32,14 +26,24 @@ func (r *RPC) Serve() {
 	}
 
 	rpc.HandleHTTP()
-	l, err := net.Listen("tcp", ":"+port)
+	r.listener, err = net.Listen("tcp", addr)
 	if err != nil {
 		panic(err)
 	}
 
-	log.Println("Serving RPC on port", port, "\n\n")
-	err = http.Serve(l, nil)
-	if err != nil {
+	log.Println("Serving RPC on", addr, "\n\n")
+	err = http.Serve(r.listener, nil)
+	if err != nil && !r.closing {
 		panic(err)
 	}
 }
+
+//makes Serve() return
+func (r *RPC) Close() {
+	if r.listener == nil {
+		return
+	}
+
+	r.closing = true
+	r.listener.Close()
+}
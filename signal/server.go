@@ -1,6 +1,7 @@
 package signal
 
 import (
+	"sort"
 	"sync"
 
 	"github.com/skycoin/net/factory"
@@ -16,10 +17,18 @@ func Listen(address string) error {
 	return DefaultServer.Listen(address)
 }
 
+func Close() {
+	DefaultServer.Close()
+}
+
 func GetClient(id uint) (client *Client, ok bool) {
 	return DefaultServer.GetClient(id)
 }
 
+func ClientIds() []uint {
+	return DefaultServer.ClientIds()
+}
+
 type Server struct {
 	factory *factory.TCPFactory
 
@@ -38,6 +47,20 @@ func (s *Server) Listen(address string) error {
 	return s.factory.Listen(address)
 }
 
+//stops listening & drops every connected client
+func (s *Server) Close() {
+	s.factory.Close()
+
+	s.fieldsMutex.Lock()
+	clients := s.clients
+	s.clients = make(map[uint]*Client)
+	s.fieldsMutex.Unlock()
+
+	for _, c := range clients {
+		c.Close()
+	}
+}
+
 func (s *Server) accept(conn *factory.Connection) {
 	var err error
 	client := newClient(conn, op2s.OPS, op2c.RESPS)
@@ -87,6 +110,21 @@ func (s *Server) GetClient(id uint) (client *Client, ok bool) {
 	return
 }
 
+//of the connected clients, in ascending order
+func (s *Server) ClientIds() []uint {
+	s.fieldsMutex.RLock()
+	ids := make([]uint, 0, len(s.clients))
+
+	for id := range s.clients {
+		ids = append(ids, id)
+	}
+
+	s.fieldsMutex.RUnlock()
+
+	sort.Slice(ids, func(i, j int) bool { return ids[i] < ids[j] })
+	return ids
+}
+
 func (s *Server) removeClient(c *Client) {
 	reg := c.GetReg()
 	if reg == nil {
@@ -0,0 +1,137 @@
+/*
+
+CLOSING TERMINALS THAT HAVE AN EXTERNAL APP ATTACHED ASKS 1ST, WHETHER
+TO DETACH THE APP (LEAVING IT RUNNING), KILL IT, OR NOT CLOSE AT ALL
+
+*/
+
+package viewport
+
+import (
+	"github.com/skycoin/viscript/hypervisor/input/mouse"
+	"github.com/skycoin/viscript/msg"
+	"github.com/skycoin/viscript/viewport/gl"
+	t "github.com/skycoin/viscript/viewport/terminal"
+)
+
+const (
+	CLOSE_PROMPT_OPTION_Detach = "Detach app, then close"
+	CLOSE_PROMPT_OPTION_Kill   = "Kill app, then close"
+	CLOSE_PROMPT_OPTION_Cancel = "Cancel"
+)
+
+var closePrompt *ClosePrompt //nil when not asking
+
+type ClosePrompt struct {
+	TermIds []msg.TerminalId //to close
+	Options []*MenuOption    //(the 1st is the question)
+}
+
+//closes them, unless an app is attached to any, in which case the user gets asked 1st
+func closeTerminals(terms []*t.Terminal) {
+	ids := []msg.TerminalId{}
+	question := ""
+
+	for _, term := range terms {
+		ids = append(ids, term.TerminalId)
+
+		if term.HasExternalAppAttached() {
+			question = "An app is attached to " + term.Label() + "."
+		}
+	}
+
+	if question == "" {
+		removeTerminals(ids)
+		return
+	}
+
+	closePrompt = &ClosePrompt{TermIds: ids}
+
+	for _, name := range []string{
+		question,
+		CLOSE_PROMPT_OPTION_Detach,
+		CLOSE_PROMPT_OPTION_Kill,
+		CLOSE_PROMPT_OPTION_Cancel} {
+
+		closePrompt.Options = append(closePrompt.Options, &MenuOption{Name: name})
+	}
+
+	centerMenuOptions(closePrompt.Options)
+	gl.SetArrowPointer()
+}
+
+func highlightClosePromptOptions() {
+	for i, option := range closePrompt.Options {
+		option.Highlighted = i > 0 && mouse.PointerIsInside(option.Bounds)
+	}
+}
+
+func drawClosePrompt() {
+	if closePrompt != nil {
+		centerMenuOptions(closePrompt.Options) //(the canvas may have been resized)
+		drawMenuOptions(closePrompt.Options)
+	}
+}
+
+//(only its options are clickable, while it's up)
+func onClosePromptClick() {
+	for _, option := range closePrompt.Options {
+		if mouse.PointerIsInside(option.Bounds) {
+			answerClosePrompt(option.Name)
+		}
+	}
+}
+
+//escape cancels
+func onClosePromptKey(m msg.MessageKey) {
+	if m.Key == msg.KeyEscape && msg.Action(m.Action) == msg.Press {
+		answerClosePrompt(CLOSE_PROMPT_OPTION_Cancel)
+	}
+}
+
+//
+//
+//private
+//
+//
+
+func answerClosePrompt(answer string) {
+	ids := closePrompt.TermIds
+
+	switch answer {
+
+	case CLOSE_PROMPT_OPTION_Detach:
+		closePrompt = nil
+		forEachTerminal(ids, (*t.Terminal).DetachExternalApp)
+		removeTerminals(ids)
+
+	case CLOSE_PROMPT_OPTION_Kill:
+		closePrompt = nil
+		forEachTerminal(ids, (*t.Terminal).KillExternalApp)
+		removeTerminals(ids)
+
+	case CLOSE_PROMPT_OPTION_Cancel:
+		closePrompt = nil
+
+	}
+}
+
+//of those still around
+func forEachTerminal(ids []msg.TerminalId, f func(*t.Terminal)) {
+	for _, id := range ids {
+		if term := t.Terms.GetTerminal(id); term != nil {
+			f(term)
+		}
+	}
+}
+
+func removeTerminals(ids []msg.TerminalId) {
+	forEachTerminal(ids, func(term *t.Terminal) {
+		t.Terms.Remove(term.TerminalId)
+	})
+}
+
+//so "close_term" asks too
+func init() {
+	t.CloseTerminals = closeTerminals
+}
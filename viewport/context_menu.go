@@ -0,0 +1,234 @@
+/*
+
+RIGHT-CLICKING A TERMINAL'S TAB OR TASKBAR BUTTON, OR THE DESKTOP, OPENS A
+CONTEXT MENU.  ITS ITEMS ARE REGISTERED BY CONTEXT (SEE
+RegisterContextMenuItems), SO ANYTHING CAN ADD ITS OWN WITHOUT TOUCHING THIS
+
+*/
+
+package viewport
+
+import (
+	"fmt"
+	"strconv"
+
+	"github.com/skycoin/viscript/app"
+	"github.com/skycoin/viscript/config"
+	"github.com/skycoin/viscript/hypervisor"
+	"github.com/skycoin/viscript/hypervisor/input/mouse"
+	"github.com/skycoin/viscript/msg"
+	"github.com/skycoin/viscript/viewport/gl"
+	t "github.com/skycoin/viscript/viewport/terminal"
+)
+
+const (
+	CONTEXT_Terminal = "terminal" //a tab or taskbar button (items get its active terminal)
+	CONTEXT_Desktop  = "desktop"  //(items get a nil terminal)
+)
+
+var (
+	contextMenu      *ContextMenu //nil when closed
+	contextMenuItems = map[string][]func(term *t.Terminal) []ContextMenuItem{}
+	movingByMenu     bool //the focused window follows the pointer till the next click
+)
+
+type ContextMenuItem struct {
+	Label string
+	Run   func(term *t.Terminal)
+}
+
+type ContextMenu struct {
+	Term    *t.Terminal //it was opened for (nil on the desktop)
+	Items   []ContextMenuItem
+	Options []*MenuOption //(one for each of Items)
+}
+
+//items is called each time a menu opens in that context, so it can
+//leave its items out (by returning none), or make one per running app, etc
+func RegisterContextMenuItems(context string, items func(term *t.Terminal) []ContextMenuItem) {
+	contextMenuItems[context] = append(contextMenuItems[context], items)
+}
+
+//opens at the pointer (unless nothing is registered for the context)
+func openContextMenu(context string, term *t.Terminal) {
+	contextMenu = nil
+	menu := &ContextMenu{Term: term}
+
+	for _, items := range contextMenuItems[context] {
+		menu.Items = append(menu.Items, items(term)...)
+	}
+
+	if len(menu.Items) == 0 {
+		return
+	}
+
+	for _, item := range menu.Items {
+		menu.Options = append(menu.Options, &MenuOption{Name: item.Label})
+	}
+
+	contextMenu = menu
+	setContextMenuBounds()
+	gl.SetArrowPointer()
+}
+
+//(right-clicking elsewhere does nothing)
+func openContextMenuUnderPointer() {
+	for _, w := range t.Terms.Visible() {
+		for _, tab := range w.Tabs {
+			if mouse.PointerIsInside(tab.TaskBarButton) {
+				openContextMenu(CONTEXT_Terminal, tab.Active())
+				return
+			}
+		}
+	}
+
+	if pointerIsOverTaskBar() {
+		return
+	}
+
+	stacked := t.Terms.Stacked()
+
+	for i := len(stacked) - 1; i >= 0; i-- {
+		w := stacked[i]
+
+		for j, tab := range w.Tabs {
+			if mouse.PointerIsInside(w.GetTabBounds(j)) {
+				openContextMenu(CONTEXT_Terminal, tab.Active())
+				return
+			}
+		}
+
+		if mouse.PointerIsInside(w.Bounds) || mouse.PointerIsInside(w.GetTabStripBounds()) {
+			return
+		}
+	}
+
+	openContextMenu(CONTEXT_Desktop, nil)
+}
+
+func highlightContextMenuOptions() {
+	for _, option := range contextMenu.Options {
+		option.Highlighted = mouse.PointerIsInside(option.Bounds)
+	}
+}
+
+func drawContextMenu() {
+	if contextMenu != nil {
+		drawMenuOptions(contextMenu.Options)
+	}
+}
+
+//runs the item clicked (if any), & closes the menu either way
+func onContextMenuClick() {
+	menu := contextMenu
+	contextMenu = nil
+
+	for i, option := range menu.Options {
+		if mouse.PointerIsInside(option.Bounds) {
+			menu.Items[i].Run(menu.Term)
+		}
+	}
+}
+
+//escape closes it.  returns whether the key was used up
+func onContextMenuKey(m msg.MessageKey) bool {
+	if m.Key == msg.KeyEscape && msg.Action(m.Action) == msg.Press {
+		contextMenu = nil
+		return true
+	}
+
+	return false
+}
+
+//
+//
+//private
+//
+//
+
+func init() {
+	RegisterContextMenuItems(CONTEXT_Terminal, func(term *t.Terminal) []ContextMenuItem {
+		items := []ContextMenuItem{
+			{"Focus", func(term *t.Terminal) {
+				t.Terms.SetFocused(term.TerminalId)
+			}},
+			{"Close", func(term *t.Terminal) {
+				closeTerminals([]*t.Terminal{term}) //(asking 1st, if an app is attached)
+			}},
+			{"Clear", func(term *t.Terminal) {
+				term.RelayToTask(msg.Serialize(
+					msg.TypeTaskAction, msg.MessageTaskAction{Action: config.ActionClear}))
+			}},
+		}
+
+		if !term.Window.Maximized && !t.Terms.IsTiled() {
+			items = append(items, ContextMenuItem{"Move", func(term *t.Terminal) {
+				t.Terms.SetFocused(term.TerminalId)
+				movingByMenu = true
+				mouse.DeltaSinceLeftClick = app.Vec2F{0, 0}
+				currentTerminalModification = TermMod_Moving
+			}})
+		}
+
+		return items
+	})
+
+	//one item for each running app, to attach to the terminal
+	RegisterContextMenuItems(CONTEXT_Terminal, func(term *t.Terminal) []ContextMenuItem {
+		if term.HasExternalAppAttached() {
+			return nil
+		}
+
+		items := []ContextMenuItem{}
+
+		for _, id := range runningAppIds() {
+			ea := hypervisor.GlobalRunningExternalApps.TaskMap[msg.ExternalAppId(id)]
+			arg := strconv.Itoa(id)
+
+			items = append(items, ContextMenuItem{
+				fmt.Sprintf("Attach app %d (%s)", id, ea.GetFullCommandLine()),
+				func(term *t.Terminal) {
+					term.RelayToTask(msg.Serialize(msg.TypeTokenizedCommand,
+						msg.MessageTokenizedCommand{Command: "attach", Args: []string{arg}}))
+				}})
+		}
+
+		return items
+	})
+
+	RegisterContextMenuItems(CONTEXT_Desktop, func(term *t.Terminal) []ContextMenuItem {
+		return []ContextMenuItem{
+			{START_MENU_OPTION_NewTerminal, func(term *t.Terminal) {
+				t.Terms.Add()
+			}},
+			{START_MENU_OPTION_Defocus, func(term *t.Terminal) {
+				t.Terms.Defocus()
+			}},
+		}
+	})
+}
+
+func pointerIsOverTaskBar() bool {
+	return mouse.GlPos.Y < -gl.CanvasExtents.Y+app.TaskBarHeight
+}
+
+//hanging down & right from the pointer, but kept on the canvas & above the taskbar
+func setContextMenuBounds() {
+	width := menuWidth(contextMenu.Options)
+	height := float32(len(contextMenu.Options)) * app.TaskBarHeight
+	left := mouse.GlPos.X
+	top := mouse.GlPos.Y
+
+	if left+width > gl.CanvasExtents.X {
+		left = gl.CanvasExtents.X - width
+	}
+
+	if top-height < -gl.CanvasExtents.Y+app.TaskBarHeight {
+		top = -gl.CanvasExtents.Y + app.TaskBarHeight + height
+	}
+
+	for _, option := range contextMenu.Options {
+		option.Bounds = &app.Rectangle{top, left + width, top - app.TaskBarHeight, left}
+		top -= app.TaskBarHeight
+	}
+}
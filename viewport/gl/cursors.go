@@ -4,20 +4,37 @@ import (
 	"time"
 
 	"github.com/skycoin/viscript/app"
+	"github.com/skycoin/viscript/config"
 )
 
-var Curs Cursors = Cursors{nextFrame: time.Now()}
+var Curs Cursors = Cursors{
+	nextFrame: time.Now(),
+	Style:     config.CursorStyleBlock,
+	Blink:     true}
 
 type Cursors struct {
+	Style string //config.CursorStyle*
+	Blink bool   //block cursors grow & shrink, the others go on & off
+
 	// private
 	nextFrame      time.Time
 	shrinking      bool
 	shrinkFraction float32
+	hidden         bool //in the off phase of blinking
 }
 
 func (c *Cursors) Tick() {
 	var speedFactor float32 = 0.06
 
+	if c.Style != config.CursorStyleBlock {
+		if c.nextFrame.Before(time.Now()) {
+			c.nextFrame = time.Now().Add(time.Millisecond * 500)
+			c.hidden = c.Blink && !c.hidden
+		}
+
+		return
+	}
+
 	if c.nextFrame.Before(time.Now()) {
 		c.nextFrame = time.Now().Add(time.Millisecond * 16) // 170 was for simple on/off blinking
 
@@ -37,13 +54,31 @@ func (c *Cursors) Tick() {
 	}
 }
 
+//rect to draw the cursor with, inside char rect r (nil when it's blinked off)
 func (c *Cursors) GetCurrentFrame(r app.Rectangle) *app.Rectangle {
-	if c.shrinking {
-		r.Bottom = r.Top - c.shrinkFraction*r.Height()
-		r.Left = r.Right - c.shrinkFraction*r.Width()
-	} else { // growing
-		r.Top = r.Bottom + c.shrinkFraction*r.Height()
-		r.Right = r.Left + c.shrinkFraction*r.Width()
+	switch c.Style {
+	case config.CursorStyleUnderline:
+		r.Top = r.Bottom + r.Height()/8
+	case config.CursorStyleBar:
+		r.Right = r.Left + r.Width()/8
+	default: //block
+		if !c.Blink {
+			return &r
+		}
+
+		if c.shrinking {
+			r.Bottom = r.Top - c.shrinkFraction*r.Height()
+			r.Left = r.Right - c.shrinkFraction*r.Width()
+		} else { // growing
+			r.Top = r.Bottom + c.shrinkFraction*r.Height()
+			r.Right = r.Left + c.shrinkFraction*r.Width()
+		}
+
+		return &r
+	}
+
+	if c.hidden {
+		return nil
 	}
 
 	return &r
@@ -131,6 +131,7 @@ func NEWERDraw9SlicedRect(r *app.PicRectangle) {
 }
 
 func drawDesktop() {
+	SetColor(Theme.Desktop[:])
 	DrawQuad(Pic_GradientBorder, desktop, 0)
 
 	/*
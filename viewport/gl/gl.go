@@ -206,6 +206,7 @@ func DrawBegin() {
 
 	gl.MatrixMode(gl.MODELVIEW) //.PROJECTION) //.MODELVIEW)
 	gl.LoadIdentity()
+	gl.ClearColor(Theme.Desktop[0], Theme.Desktop[1], Theme.Desktop[2], 0)
 	gl.Clear(gl.COLOR_BUFFER_BIT | gl.DEPTH_BUFFER_BIT)
 	gl.Translatef(0, 0, -DistanceFromOrigin)
 
@@ -17,8 +17,6 @@ func PollEvents() {
 }
 
 func InitInputEvents(w *glfw.Window) {
-	//ui
-	w.SetCloseCallback(onClose)
 	//keyboard
 	w.SetCharCallback(onChar)
 	w.SetKeyCallback(onKey)
@@ -28,11 +26,9 @@ func InitInputEvents(w *glfw.Window) {
 	w.SetCursorPosCallback(onMouseCursorPos)
 }
 
-func onClose(w *glfw.Window) {
-	msg.SerializeAndDispatch(
-		InputEvents,
-		msg.TypeKey,
-		msg.MessageKey{Key: msg.KeyEscape})
+//the window's close button sets this
+func WindowShouldClose() bool {
+	return GlfwWindow.ShouldClose()
 }
 
 //apparently every time this is fired, a mouse position event is ALSO fired
@@ -102,3 +98,18 @@ func eitherControlKeyHeld(w *glfw.Window) bool {
 
 	return false
 }
+
+//the system clipboard (for copying & pasting text selected in terminals)
+func SetClipboard(s string) {
+	GlfwWindow.SetClipboardString(s)
+}
+
+//empty when there's no text on it
+func GetClipboard() string {
+	s, err := GlfwWindow.GetClipboardString()
+	if err != nil {
+		return ""
+	}
+
+	return s
+}
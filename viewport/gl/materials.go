@@ -23,6 +23,7 @@ var Fuschia = []float32{0.6, 0.2, 0.3, 1}
 var Gray = []float32{0.25, 0.25, 0.25, 1}
 var GrayDark = []float32{0.15, 0.15, 0.15, 1}
 var GrayLight = []float32{0.4, 0.4, 0.4, 1}
+var GrayMedium = []float32{0.5, 0.5, 0.5, 1}
 var Green = []float32{0, 1, 0, 1}
 var Magenta = []float32{1, 0, 1, 1}
 var Maroon = []float32{0.5, 0.03, 0.207, 1}
@@ -0,0 +1,85 @@
+package gl
+
+import (
+	"github.com/skycoin/viscript/config"
+)
+
+//colors everything is drawn with (see the "theme:" section in config.yaml).
+//pass them to SetColor like so: SetColor(Theme.Desktop[:])
+var Theme, _ = config.Theme{}.Resolve()
+
+var themeGeneration = -1 //config generation Theme came from
+
+//picks up the configured theme, when the config (re)loaded since last time
+func ApplyThemeIfChanged() {
+	if themeGeneration == config.Generation() {
+		return
+	}
+
+	themeGeneration = config.Generation()
+
+	p, err := config.Global.Theme.Resolve()
+	if err != nil { //a loaded config is always valid, but just in case
+		println(err.Error())
+		return
+	}
+
+	Theme = p
+	Curs.Style = p.CursorStyle
+	Curs.Blink = p.CursorBlink
+}
+
+//for the chars & cursor of a terminal
+func TextColor(focused bool) []float32 {
+	if focused {
+		return Theme.TextFocused[:]
+	}
+
+	return Theme.TextUnfocused[:]
+}
+
+func FrameColor(focused bool) []float32 {
+	if focused {
+		return Theme.FrameFocused[:]
+	}
+
+	return Theme.FrameUnfocused[:]
+}
+
+func WindowColor(focused bool) []float32 {
+	if focused {
+		return Theme.WindowFocused[:]
+	}
+
+	return Theme.WindowUnfocused[:]
+}
+
+func SameColor(a, b []float32) bool {
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+
+	return len(a) == len(b)
+}
+
+//for buttons & menu items on the taskbar
+func TaskbarColors(active bool) (background, text []float32) {
+	if active {
+		return Theme.TaskbarButtonActive[:], Theme.TaskbarTextActive[:]
+	}
+
+	return Theme.TaskbarButton[:], Theme.TaskbarText[:]
+}
+
+//a copy, halfway to black (for things like buttons of minimized windows)
+func Dimmed(color []float32) []float32 {
+	c := append([]float32{}, color...)
+
+	for i := 0; i < 3 && i < len(c); i++ {
+		c[i] *= 0.5
+	}
+
+	return c
+}
@@ -6,8 +6,6 @@ import (
 	"github.com/skycoin/viscript/config"
 	"github.com/skycoin/viscript/hypervisor/input/keyboard"
 	"github.com/skycoin/viscript/msg"
-	"github.com/skycoin/viscript/viewport/gl"
-	t "github.com/skycoin/viscript/viewport/terminal"
 )
 
 func onChar(m msg.MessageChar) {
@@ -40,10 +38,6 @@ func onKey(m msg.MessageKey) {
 	if msg.Action(m.Action) == msg.Release {
 		switch m.Key {
 
-		case msg.KeyEscape:
-			println("\n\nCLOSING OPENGL WINDOW")
-			CloseWindow = true
-
 		case msg.KeyLeftShift:
 			fallthrough
 		case msg.KeyRightShift:
@@ -89,15 +83,9 @@ func onKey(m msg.MessageKey) {
 			keyboard.SuperKeyIsDown = true
 		}
 
-		//keys
+		//keys (see keybindings.go for the ones that do things)
 		switch m.Key {
 
-		case msg.KeyLeftControl:
-			fallthrough
-		case msg.KeyRightControl:
-			t.Terms.Defocus()
-			gl.SetArrowPointer()
-
 			/*
 				case glfw.KeyEnter:
 					startOfLine := b[foc.CursY][:foc.CursX]
@@ -2,7 +2,6 @@ package viewport
 
 import (
 	"fmt"
-	"strings"
 
 	"github.com/skycoin/viscript/app"
 	"github.com/skycoin/viscript/config"
@@ -12,14 +11,22 @@ import (
 	t "github.com/skycoin/viscript/viewport/terminal"
 )
 
-var currentTerminalModification int
+var (
+	currentTerminalModification int
+	resizingEdges               int       //(t.Edge* flags) of the focused window, while TermMod_Resizing
+	movedSinceClick             bool      //(so just clicking a window by the canvas edge won't dock it)
+	draggedTaskBarWindow        *t.Window //whose buttons are being dragged along the taskbar
+	minimizeOnRelease           *t.Window //(pressed the button of what was already focused, without dragging it yet)
+	scrollingFrom               int       //backscroll amount when the scrollbar's thumb was grabbed
+)
 
 const (
 	TermMod_None = iota
 	TermMod_Moving
-	TermMod_ResizingX
-	TermMod_ResizingY
-	TermMod_ResizingBoth
+	TermMod_Resizing    //by resizingEdges
+	TermMod_DraggingTab //(1 of several, which gets detached once it leaves the strip of tabs)
+	TermMod_Selecting   //text of the focused terminal
+	TermMod_Scrolling   //by dragging the thumb of the focused terminal's scrollbar
 )
 
 // triggered both by moving **AND*** by pressing buttons
@@ -33,18 +40,34 @@ func onMouseCursorPos(m msg.MessageMousePos) {
 
 	mouse.Update(app.Vec2F{float32(m.X), float32(m.Y)})
 
+	highlightTrayItems()
+
 	//detect hovering over start menu options
 	if startMenuOpen {
-		for _, option := range startMenu {
-			if mouse.PointerIsInside(option.Bounds) {
-				option.Highlighted = true
-			} else {
-				option.Highlighted = false
-			}
-		}
+		highlightStartMenuUnderPointer()
 	}
 
-	foc := t.Terms.GetFocusedTerminal()
+	if closePrompt != nil {
+		highlightClosePromptOptions()
+		return
+	}
+
+	if paramPrompt != nil {
+		highlightParamPromptOptions()
+		return
+	}
+
+	if contextMenu != nil {
+		highlightContextMenuOptions()
+		return
+	}
+
+	if draggedTaskBarWindow != nil {
+		dragTaskBarButtons()
+		return
+	}
+
+	foc := t.Terms.GetFocusedWindow()
 	if foc == nil {
 		return
 	}
@@ -57,16 +80,34 @@ func onMouseCursorPos(m msg.MessageMousePos) {
 		//high resolution delta for potentially subpixel precision resizing
 		delt := mouse.GlPos.GetDeltaFrom(mouse.PrevGlPos)
 		t.Terms.MoveFocusedTerminal(delt, &mouse.DeltaSinceLeftClick)
+		movedSinceClick = movedSinceClick || delt != (app.Vec2F{0, 0})
 		//gl.SetHandPointer()
 
-	case TermMod_ResizingX:
-		foc.ResizeHorizontally(mouse.GlPos.X)
-	case TermMod_ResizingY:
-		foc.ResizeVertically(mouse.GlPos.Y)
+	case TermMod_Resizing:
+		foc.Resize(resizingEdges, mouse.GlPos)
 
-	case TermMod_ResizingBoth:
-		foc.ResizeHorizontally(mouse.GlPos.X)
-		foc.ResizeVertically(mouse.GlPos.Y)
+	case TermMod_Selecting:
+		if term := t.Terms.GetFocusedTerminal(); term != nil {
+			term.ExtendSelection(mouse.GlPos)
+		}
+
+	case TermMod_Scrolling:
+		if term := t.Terms.GetFocusedTerminal(); term != nil {
+			term.DragScrollBar(scrollingFrom, mouse.DeltaSinceLeftClick.Y)
+		}
+
+	case TermMod_DraggingTab:
+		if !mouse.PointerIsInside(foc.GetTabStripBounds()) {
+			t.Terms.DetachTab(foc, foc.ActiveTab, mouse.GlPos)
+
+			//carry on dragging the new window around
+			mouse.DeltaSinceLeftClick = app.Vec2F{0, 0}
+			currentTerminalModification = TermMod_Moving
+
+			if t.Terms.IsTiled() {
+				currentTerminalModification = TermMod_None
+			}
+		}
 
 	}
 }
@@ -114,6 +155,30 @@ func actOnPress(m msg.MessageMouseButton) {
 	case msg.MouseButtonLeft:
 		mouse.LeftButtonIsDown = true
 		mouse.DeltaSinceLeftClick = app.Vec2F{0, 0}
+		mouse.OnLeftPress()
+		movedSinceClick = false
+
+		if closePrompt != nil { //(nothing else can be clicked till it's answered)
+			onClosePromptClick()
+			break
+		}
+
+		if paramPrompt != nil { //(same)
+			onParamPromptClick()
+			break
+		}
+
+		if contextMenu != nil { //(any click closes it)
+			onContextMenuClick()
+			break
+		}
+
+		if movingByMenu { //(put it down where it is)
+			movingByMenu = false
+			currentTerminalModification = TermMod_None
+			t.Terms.DropFocusedTerminal(mouse.GlPos)
+			break
+		}
 		justClosedStartMenu := false
 
 		cr := &app.Rectangle{ //current rect (starting with ENTIRE taskbar)
@@ -122,45 +187,12 @@ func actOnPress(m msg.MessageMouseButton) {
 			-gl.CanvasExtents.Y,
 			-gl.CanvasExtents.X}
 
-		//detect clicks in start menu
+		//detect clicks in start menu (which closes it, unless a submenu was opened)
 		if startMenuOpen {
-			//detect clicks on option buttons
-			for _, option := range startMenu {
-				if mouse.PointerIsInside(option.Bounds) {
-					switch option.Name {
-
-					case START_MENU_OPTION_Divider:
-						//just ignore it
-
-					case START_MENU_OPTION_Defocus:
-						t.Terms.Defocus()
-
-					case START_MENU_OPTION_NewTerminal:
-						t.Terms.Add()
-
-					default: //must be a [start app] button
-						//make sure at least 1 terminal exists
-						if len(t.Terms.TermMap) < 1 {
-							t.Terms.Add()
-						}
-
-						//pipe the [start app] command to focused terminal
-						s := strings.TrimSpace(option.Name)
-						s = strings.ToLower(s)
-						tokens := strings.Split(s, " ")
-						tc := msg.MessageTokenizedCommand{tokens[0], tokens[1:]}
-						m := msg.Serialize(msg.TypeTokenizedCommand, tc)
-						t.Terms.GetFocusedTerminal().RelayToTask(m)
-
-					}
-
-					//after all cases...
-					startMenuOpen = false
-					break
-				}
+			if onStartMenuClick() {
+				break
 			}
 
-			startMenuOpen = false
 			justClosedStartMenu = true
 		}
 
@@ -170,16 +202,37 @@ func actOnPress(m msg.MessageMouseButton) {
 
 			if mouse.PointerIsInside(cr) { //(start button)
 				if !justClosedStartMenu {
-					startMenuOpen = !startMenuOpen
+					openStartMenu(false)
 				}
 
 				break
 			}
 
-			//detect clicks in Terminal buttons
-			for _, term := range t.Terms.TermMap {
-				if mouse.PointerIsInside(term.TaskBarButton) {
-					t.Terms.SetFocused(term.TerminalId)
+			if onTrayClick() {
+				break
+			}
+
+			//detect clicks in the workspace switcher
+			for _, ws := range t.Terms.Workspaces {
+				if mouse.PointerIsInside(ws.TaskBarButton) {
+					t.Terms.SwitchWorkspace(ws.Name)
+					break
+				}
+			}
+
+			//detect clicks in tab buttons (grouped by window).
+			//clicking the focused one minimizes it, & clicking a minimized one brings it back
+			for _, w := range t.Terms.Visible() {
+				for _, tab := range w.Tabs {
+					if mouse.PointerIsInside(tab.TaskBarButton) {
+						if !w.Minimized && w == t.Terms.GetFocusedWindow() && tab == w.Tab() {
+							minimizeOnRelease = w
+						} else {
+							t.Terms.SetFocused(tab.Active().TerminalId)
+						}
+
+						draggedTaskBarWindow = w
+					}
 				}
 			}
 		} else { //respond to any desktop clicks
@@ -187,7 +240,18 @@ func actOnPress(m msg.MessageMouseButton) {
 				closeOrFocusOnTopmostTermThatPointerTouches()
 			}
 
-			currentTerminalModification = getTerminalModificationByZone()
+			currentTerminalModification = getTerminalModificationByZone(m.Mod)
+		}
+
+	case msg.MouseButtonMiddle:
+		if closePrompt == nil && paramPrompt == nil {
+			pasteIntoFocused()
+		}
+
+	case msg.MouseButtonRight:
+		if closePrompt == nil && paramPrompt == nil && !movingByMenu {
+			closeStartMenu()
+			openContextMenuUnderPointer()
 		}
 
 	}
@@ -198,41 +262,55 @@ func actOnRelease(m msg.MessageMouseButton) {
 
 	case msg.MouseButtonLeft:
 		mouse.LeftButtonIsDown = false
+
+		if movingByMenu { //(keeps following the pointer till the next click)
+			break
+		}
+
+		if currentTerminalModification == TermMod_Moving && movedSinceClick {
+			t.Terms.DropFocusedTerminal(mouse.GlPos) //(which may dock it)
+		}
+
 		currentTerminalModification = TermMod_None
+		draggedTaskBarWindow = nil
+
+		if minimizeOnRelease != nil && mouse.PointerIsInside(minimizeOnRelease.TaskBarButton) {
+			t.Terms.Minimize(minimizeOnRelease)
+		}
+
+		minimizeOnRelease = nil
 
 	}
 }
 
 func setPointerBasedOnPosition() {
-	foc := t.Terms.GetFocusedTerminal()
+	foc := t.Terms.GetFocusedWindow()
 
-	if foc == nil {
+	if foc == nil || scrollBarUnderPointer(t.Terms.GetFocusedTerminal()) != nil {
 		gl.SetArrowPointer()
 	} else {
-		if !foc.FixedSize {
-			//at bottom right corner
-			if mouse.NearRight(foc.Bounds) &&
-				mouse.NearBottom(foc.Bounds) {
+		if !foc.FixedSize && !foc.Maximized && !t.Terms.IsTiled() {
+			edges := edgesNearPointer(foc)
+			horizontal := edges&(t.EdgeLeft|t.EdgeRight) != 0
+			vertical := edges&(t.EdgeTop|t.EdgeBottom) != 0
+
+			switch {
 
+			case horizontal && vertical: //at a corner
 				gl.SetCornerResizePointer()
 				return
-			}
-
-			//at right edge
-			if mouse.NearRight(foc.Bounds) {
+			case horizontal:
 				gl.SetHResizePointer()
 				return
-			}
-
-			//at bottom edge
-			if mouse.NearBottom(foc.Bounds) {
+			case vertical:
 				gl.SetVResizePointer()
 				return
+
 			}
 		}
 
 		if mouse.PointerIsInside(foc.Bounds) ||
-			mouse.PointerIsInside(foc.GetTabBounds()) {
+			mouse.PointerIsInside(foc.GetTabStripBounds()) {
 
 			gl.SetHandPointer()
 			//gl.SetIBeamPointer() //IBeam is harder to see...
@@ -244,41 +322,81 @@ func setPointerBasedOnPosition() {
 	}
 }
 
-func closeOrFocusOnTopmostTermThatPointerTouches() {
-	var topmostZ float32
-	var topmostId msg.TerminalId
-	clickedCloseButton := false
+//once the pointer passes the middle of another window's buttons, they swap places
+func dragTaskBarButtons() {
+	dragged := draggedTaskBarWindow.TaskBarButton
+	x := mouse.GlPos.X
 
-	for _, term := range t.Terms.TermMap {
-		tb := term.GetTabBounds()
+	for _, w := range t.Terms.Visible() {
+		b := w.TaskBarButton
 
-		if mouse.PointerIsInside(term.Bounds) ||
-			mouse.PointerIsInside(tb) {
+		if w != draggedTaskBarWindow && x >= b.Left && x <= b.Right {
+			if (b.Left > dragged.Left && x > b.CenterX()) ||
+				(b.Left < dragged.Left && x < b.CenterX()) {
 
-			if mouse.PointerIsInside(getCloseButtonRect(term, tb)) {
-				clickedCloseButton = true
+				t.Terms.MoveInTaskBar(draggedTaskBarWindow, w)
+				minimizeOnRelease = nil
 			}
 
-			if topmostZ < term.Depth {
-				topmostZ = term.Depth
-				topmostId = term.TerminalId
+			return
+		}
+	}
+}
+
+func closeOrFocusOnTopmostTermThatPointerTouches() {
+	var topmost *t.Window
+	stacked := t.Terms.Stacked()
+
+	for i := len(stacked) - 1; i >= 0; i-- {
+		if mouse.PointerIsInside(stacked[i].Bounds) ||
+			mouse.PointerIsInside(stacked[i].GetTabStripBounds()) {
+
+			topmost = stacked[i]
+			break
+		}
+	}
+
+	if topmost == nil {
+		return
+	}
+
+	for i, tab := range topmost.Tabs {
+		switch {
+
+		case mouse.PointerIsInside(topmost.GetTabButtonBounds(i, t.TabButtonClose)):
+			closeTerminals(tab.Terms()) //(asking 1st, if an app is attached)
+			return
+
+		case mouse.PointerIsInside(topmost.GetTabButtonBounds(i, t.TabButtonMaximize)):
+			t.Terms.ToggleMaximized(topmost)
+			return
+
+		case mouse.PointerIsInside(topmost.GetTabButtonBounds(i, t.TabButtonMinimize)):
+			t.Terms.Minimize(topmost)
+			return
+
+		case mouse.PointerIsInside(topmost.GetTabBounds(i)):
+			t.Terms.SetFocused(tab.Active().TerminalId)
+
+			if mouse.ClickCount == 2 {
+				t.Terms.ToggleMaximized(topmost)
 			}
+
+			return
+
 		}
 	}
 
-	if topmostZ > 0 {
-		if clickedCloseButton {
-			t.Terms.Remove(topmostId)
-		} else {
-			t.Terms.SetFocused(topmostId)
+	//focus the pane clicked on
+	id := topmost.Active().TerminalId
+
+	for _, term := range topmost.Terms() {
+		if mouse.PointerIsInside(term.Bounds) {
+			id = term.TerminalId
 		}
 	}
-}
 
-func getCloseButtonRect(term *t.Terminal, tabRect *app.Rectangle) *app.Rectangle {
-	tabRect.Left = tabRect.Right - term.CharSize.X - term.BorderSize*2
-	tabRect.Bottom = tabRect.Top - term.CharSize.Y - term.BorderSize
-	return tabRect
+	t.Terms.SetFocused(id)
 }
 
 func convertClickToTextCursorPosition(button, action uint8) {
@@ -324,32 +442,109 @@ func showFloat64(s string, f float64) {
 	fmt.Printf("   [%s: %.1f]", s, f)
 }
 
-func getTerminalModificationByZone() int {
-	foc := t.Terms.GetFocusedTerminal()
+func getTerminalModificationByZone(mod uint8) int {
+	foc := t.Terms.GetFocusedWindow()
 
 	if foc == nil {
 		return TermMod_None
 	}
 
-	if !foc.FixedSize {
-		if mouse.NearRight(foc.Bounds) &&
-			mouse.NearBottom(foc.Bounds) {
+	if len(foc.Tabs) > 1 &&
+		mouse.PointerIsInside(foc.GetTabBounds(foc.ActiveTab)) {
+
+		return TermMod_DraggingTab
+	}
+
+	//the scrollbar (inside the frame, so it comes before resizing).
+	//the thumb gets dragged, & clicking beside it pages toward the pointer
+	term := t.Terms.GetFocusedTerminal()
 
-			return TermMod_ResizingBoth
+	if scrollBarUnderPointer(term) != nil {
+		if mouse.PointerIsInside(term.ScrollThumbBounds()) {
+			scrollingFrom, _ = term.Backscroll()
+			return TermMod_Scrolling
 		}
 
-		if /****/ mouse.NearRight(foc.Bounds) {
-			return TermMod_ResizingX
-		} else if mouse.NearBottom(foc.Bounds) {
-			return TermMod_ResizingY
+		term.PageScrollBarToward(mouse.GlPos)
+		return TermMod_None
+	}
+
+	movable := !foc.Maximized && !t.Terms.IsTiled() //(tiles can't be moved or resized by hand)
+
+	if movable && !foc.FixedSize {
+		resizingEdges = edgesNearPointer(foc)
+
+		if resizingEdges != 0 {
+			return TermMod_Resizing
 		}
 	}
 
+	//dragging over text selects it (alt+dragging moves the window from anywhere)
+	if term != nil && term.TextAreaContains(mouse.GlPos) &&
+		(msg.ModifierKey(mod)&msg.ModAlt == 0 || !movable) {
+
+		term.StartSelection(mouse.GlPos, selectionUnit())
+		return TermMod_Selecting
+	}
+
+	if !movable {
+		return TermMod_None
+	}
+
 	if mouse.PointerIsInside(foc.Bounds) ||
-		mouse.PointerIsInside(foc.GetTabBounds()) {
+		mouse.PointerIsInside(foc.GetTabStripBounds()) {
 
 		return TermMod_Moving
 	} else {
 		return TermMod_None
 	}
 }
+
+//nil unless the terminal shows one & the pointer is over it
+func scrollBarUnderPointer(term *t.Terminal) *app.Rectangle {
+	if term == nil {
+		return nil
+	}
+
+	bar := term.ScrollBarBounds()
+	if bar == nil || !mouse.PointerIsInside(bar) {
+		return nil
+	}
+
+	return bar
+}
+
+//chars, words or lines, by single, double or triple click
+func selectionUnit() int {
+	switch (mouse.ClickCount - 1) % 3 {
+	case 1:
+		return t.SelectWords
+	case 2:
+		return t.SelectLines
+	}
+
+	return t.SelectChars
+}
+
+//of the window's frame (as t.Edge* flags)
+func edgesNearPointer(w *t.Window) int {
+	edges := 0
+
+	if mouse.NearLeft(w.Bounds) {
+		edges |= t.EdgeLeft
+	}
+
+	if mouse.NearRight(w.Bounds) {
+		edges |= t.EdgeRight
+	}
+
+	if mouse.NearTop(w.Bounds) {
+		edges |= t.EdgeTop
+	}
+
+	if mouse.NearBottom(w.Bounds) {
+		edges |= t.EdgeBottom
+	}
+
+	return edges
+}
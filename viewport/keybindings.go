@@ -0,0 +1,208 @@
+package viewport
+
+/*
+	Key bindings (the "keybindings:" config section) are checked before
+	keys reach the focused terminal's task.  Ones that are used up never
+	get there, & neither does the char of the same key press.
+*/
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/skycoin/viscript/config"
+	"github.com/skycoin/viscript/msg"
+	"github.com/skycoin/viscript/viewport/gl"
+	t "github.com/skycoin/viscript/viewport/terminal"
+)
+
+const leaderTimeout = 2 * time.Second //to press a chord after the leader
+
+var (
+	keyMap           config.KeyMap
+	keyMapGeneration = -1      //config generation keyMap came from
+	leaderPressedAt  time.Time //zero when not waiting for a chord after the leader
+	swallowChars     bool      //the last key press was a binding
+)
+
+//returns whether the key was used up by a binding
+func onKeyBinding(m msg.MessageKey) bool {
+	if msg.Action(m.Action) == msg.Release || isModifierKey(m.Key) {
+		return false
+	}
+
+	if keyMapGeneration != config.Generation() {
+		keyMapGeneration = config.Generation()
+
+		//(a loaded config was checked already, but just in case, keep the old keys)
+		if km, err := config.Global.Keys.Resolve(); err == nil {
+			keyMap = km
+		} else {
+			println("Keeping the previous keybindings:", err.Error())
+		}
+	}
+
+	chord := config.Chord{Key: m.Key, Mod: m.Mod}
+	swallowChars = false
+
+	if !leaderPressedAt.IsZero() {
+		afterLeader := time.Since(leaderPressedAt) < leaderTimeout
+		leaderPressedAt = time.Time{}
+
+		if afterLeader {
+			//pressing the leader twice sends it on, like tmux does.
+			//(a reload may have removed the leader meanwhile)
+			if keyMap.Leader != nil && chord == *keyMap.Leader {
+				return false
+			}
+
+			//anything else after the leader is used up, bound or not
+			swallowChars = true
+			runKeyAction(keyMap.AfterLeader[chord])
+			return true
+		}
+	}
+
+	if keyMap.Leader != nil && chord == *keyMap.Leader {
+		if msg.Action(m.Action) == msg.Press {
+			leaderPressedAt = time.Now()
+		}
+
+		swallowChars = true
+		return true
+	}
+
+	action, bound := keyMap.Direct[chord]
+	if !bound {
+		return false
+	}
+
+	//holding a bound chord down shouldn't do it over & over
+	if msg.Action(m.Action) == msg.Press {
+		runKeyAction(action)
+	}
+
+	swallowChars = true
+	return true
+}
+
+//
+//
+//private
+//
+//
+
+func runKeyAction(action string) {
+	switch action {
+
+	case config.ActionNewTerm:
+		t.Terms.AddWithFixedSizeState(true)
+
+	case config.ActionCloseTerm:
+		if foc := t.Terms.GetFocusedTerminal(); foc != nil {
+			closeTerminals([]*t.Terminal{foc})
+		}
+
+	case config.ActionFocusNext:
+		t.Terms.FocusNext(1)
+
+	case config.ActionFocusPrev:
+		t.Terms.FocusNext(-1)
+
+	case config.ActionDefocus:
+		t.Terms.Defocus()
+		gl.SetArrowPointer()
+
+	case config.ActionLayoutNext:
+		t.Terms.NextLayout()
+
+	case config.ActionLayoutFloating, config.ActionLayoutGrid, config.ActionLayoutMaster,
+		config.ActionLayoutSplitH, config.ActionLayoutSplitV:
+		t.Terms.SetLayout(strings.TrimPrefix(action, "layout_"))
+
+	case config.ActionSplitH, config.ActionSplitV:
+		t.Terms.Split(t.Terms.FocusedId, action == config.ActionSplitH)
+
+	case config.ActionPaneNext:
+		t.Terms.FocusPane(1)
+
+	case config.ActionPanePrev:
+		t.Terms.FocusPane(-1)
+
+	case config.ActionNewTab:
+		if w := t.Terms.GetFocusedWindow(); w != nil {
+			t.Terms.AddTab(w)
+		}
+
+	case config.ActionTabNext:
+		t.Terms.FocusTab(1)
+
+	case config.ActionTabPrev:
+		t.Terms.FocusTab(-1)
+
+	case config.ActionWorkspaceNext:
+		t.Terms.NextWorkspace(1)
+
+	case config.ActionWorkspacePrev:
+		t.Terms.NextWorkspace(-1)
+
+	case config.ActionCopy:
+		if foc := t.Terms.GetFocusedTerminal(); foc != nil && foc.Selection.Active {
+			gl.SetClipboard(foc.SelectedText())
+		}
+
+	case config.ActionPaste:
+		pasteIntoFocused()
+
+	case config.ActionStartMenu:
+		if startMenuOpen {
+			closeStartMenu()
+		} else {
+			openStartMenu(true)
+		}
+
+	case config.ActionQuit:
+		println("\n\nCLOSING OPENGL WINDOW")
+		CloseWindow = true
+
+	case config.ActionClear, config.ActionDetach, config.ActionInterrupt:
+		foc := t.Terms.GetFocusedTerminal()
+
+		if foc != nil {
+			foc.RelayToTask(msg.Serialize(
+				msg.TypeTaskAction, msg.MessageTaskAction{Action: action}))
+		}
+
+	default:
+		//workspace_1 to 9
+		if n, err := strconv.Atoi(strings.TrimPrefix(action, config.ActionWorkspaceN)); err == nil &&
+			n <= len(t.Terms.Workspaces) {
+
+			t.Terms.SwitchWorkspace(t.Terms.Workspaces[n-1].Name)
+		}
+
+	}
+}
+
+//the clipboard's text, into the focused terminal's command line (or app)
+func pasteIntoFocused() {
+	foc := t.Terms.GetFocusedTerminal()
+	text := gl.GetClipboard()
+
+	if foc != nil && text != "" {
+		foc.RelayToTask(msg.Serialize(msg.TypePaste, msg.MessagePaste{text}))
+	}
+}
+
+func isModifierKey(key uint32) bool {
+	switch key {
+	case msg.KeyLeftShift, msg.KeyRightShift,
+		msg.KeyLeftControl, msg.KeyRightControl,
+		msg.KeyLeftAlt, msg.KeyRightAlt,
+		msg.KeyLeftSuper, msg.KeyRightSuper:
+		return true
+	}
+
+	return false
+}
@@ -35,14 +35,33 @@ func UnpackMessage(msgIn []byte) []byte {
 		msg.MustDeserialize(msgIn, &m)
 		onChar(m)
 
-		passOnToFocused(msgIn)
+		switch {
+		case swallowChars, closePrompt != nil:
+		case paramPrompt != nil:
+			onParamPromptChar(m)
+		case startMenuOpen:
+			onStartMenuChar(m) //(filters it)
+		default:
+			passOnToFocused(msgIn)
+		}
 
 	case msg.TypeKey:
 		var m msg.MessageKey
 		msg.MustDeserialize(msgIn, &m)
 		onKey(m)
 
-		passOnToFocused(msgIn)
+		switch {
+		case closePrompt != nil:
+			onClosePromptKey(m)
+		case paramPrompt != nil:
+			onParamPromptKey(m)
+		case startMenuOpen && onStartMenuKey(m):
+			//(used up)
+		case contextMenu != nil && onContextMenuKey(m):
+			//(closed it)
+		case !onKeyBinding(m):
+			passOnToFocused(msgIn)
+		}
 
 	case msg.TypeFrameBufferSize:
 		var m msg.MessageFrameBufferSize
@@ -65,6 +84,7 @@ func actOnFrameBufferSize(m msg.MessageFrameBufferSize) {
 	}
 
 	gl.SetSize(int32(m.X), int32(m.Y))
+	t.Terms.ApplyLayout()
 }
 
 func passOnToFocused(msgIn []byte) {
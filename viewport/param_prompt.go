@@ -0,0 +1,205 @@
+/*
+
+"START <APP>" IN THE START MENU ASKS FOR THE APP'S PARAMETERS 1ST (OR FOR
+ITS ARGS, WHEN NO "params:" ARE CONFIGURED), INSTEAD OF STARTING IT WITH
+ONLY ITS DEFAULTS.  WHATEVER IS LEFT EMPTY GETS ITS DEFAULT
+
+*/
+
+package viewport
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/skycoin/viscript/config"
+	"github.com/skycoin/viscript/hypervisor/input/mouse"
+	"github.com/skycoin/viscript/msg"
+	"github.com/skycoin/viscript/viewport/gl"
+)
+
+const (
+	PARAM_PROMPT_OPTION_Start  = "Start"
+	PARAM_PROMPT_OPTION_Cancel = "Cancel"
+)
+
+var paramPrompt *ParamPrompt //nil when not asking
+
+type ParamPrompt struct {
+	AppName string
+	Fields  []*ParamField
+	Focused int           //index of Fields, which typing goes into
+	Options []*MenuOption //the title, a line for each field, then start & cancel
+}
+
+type ParamField struct {
+	Label string //name, type, & default
+	Name  string //for passing the value as name=value (empty for plain args)
+	Value string
+}
+
+func openParamPrompt(appName string) {
+	a := config.Global.Apps[appName]
+	paramPrompt = &ParamPrompt{AppName: appName}
+
+	for _, p := range a.Params {
+		label := p.Name + " (" + p.TypeOrDefault()
+
+		switch {
+		case p.Required:
+			label += ", required"
+		case p.Default != "":
+			label += ", default: " + p.Default
+		}
+
+		paramPrompt.Fields = append(paramPrompt.Fields,
+			&ParamField{Label: label + ")", Name: p.Name})
+	}
+
+	if len(a.Params) == 0 {
+		label := "args"
+		if len(a.Args) > 0 {
+			label += " (default: " + strings.Join(a.Args, " ") + ")"
+		}
+
+		paramPrompt.Fields = append(paramPrompt.Fields, &ParamField{Label: label})
+	}
+
+	paramPrompt.Options = append(paramPrompt.Options, &MenuOption{Name: "Start " + appName + ":"})
+
+	for range paramPrompt.Fields {
+		paramPrompt.Options = append(paramPrompt.Options, &MenuOption{})
+	}
+
+	paramPrompt.Options = append(paramPrompt.Options,
+		&MenuOption{Name: PARAM_PROMPT_OPTION_Start},
+		&MenuOption{Name: PARAM_PROMPT_OPTION_Cancel})
+
+	gl.SetArrowPointer()
+}
+
+//the focused field stays lit, as do start & cancel while pointed at
+func highlightParamPromptOptions() {
+	for i, option := range paramPrompt.Options {
+		option.Highlighted = i == paramPrompt.Focused+1 ||
+			i > len(paramPrompt.Fields) && mouse.PointerIsInside(option.Bounds)
+	}
+}
+
+func drawParamPrompt() {
+	if paramPrompt == nil {
+		return
+	}
+
+	for i, field := range paramPrompt.Fields {
+		s := field.Label + ": " + field.Value
+
+		if i == paramPrompt.Focused {
+			s += "_"
+		}
+
+		paramPrompt.Options[i+1].Name = s
+	}
+
+	centerMenuOptions(paramPrompt.Options)
+	highlightParamPromptOptions()
+	drawMenuOptions(paramPrompt.Options)
+}
+
+//(only it can be clicked on, while it's up)
+func onParamPromptClick() {
+	for i, option := range paramPrompt.Options {
+		if option.Bounds == nil || !mouse.PointerIsInside(option.Bounds) {
+			continue
+		}
+
+		switch num := len(paramPrompt.Fields); {
+		case i == num+1:
+			answerParamPrompt(true)
+		case i == num+2:
+			answerParamPrompt(false)
+		case i > 0:
+			paramPrompt.Focused = i - 1
+		}
+
+		return
+	}
+}
+
+//tab & the arrows move between fields, enter starts the app & escape cancels
+func onParamPromptKey(m msg.MessageKey) {
+	if msg.Action(m.Action) == msg.Release {
+		return
+	}
+
+	field := paramPrompt.Fields[paramPrompt.Focused]
+	num := len(paramPrompt.Fields)
+
+	switch m.Key {
+
+	case msg.KeyEscape:
+		answerParamPrompt(false)
+
+	case msg.KeyEnter, msg.KeyKPEnter:
+		answerParamPrompt(true)
+
+	case msg.KeyTab, msg.KeyDown:
+		if msg.ModifierKey(m.Mod)&msg.ModShift != 0 {
+			paramPrompt.Focused = (paramPrompt.Focused + num - 1) % num
+		} else {
+			paramPrompt.Focused = (paramPrompt.Focused + 1) % num
+		}
+
+	case msg.KeyUp:
+		paramPrompt.Focused = (paramPrompt.Focused + num - 1) % num
+
+	case msg.KeyBackspace:
+		field.Value = withoutLastRune(field.Value)
+
+	}
+}
+
+func onParamPromptChar(m msg.MessageChar) {
+	if m.Char >= ' ' {
+		field := paramPrompt.Fields[paramPrompt.Focused]
+		field.Value += string(rune(m.Char))
+	}
+}
+
+//
+//
+//private
+//
+//
+
+//"start" (in the focused terminal, so it reports any problem with them)
+func answerParamPrompt(start bool) {
+	pp := paramPrompt
+	paramPrompt = nil
+
+	if !start {
+		return
+	}
+
+	args := []string{pp.AppName}
+
+	for _, field := range pp.Fields {
+		value := strings.TrimSpace(field.Value)
+
+		switch {
+		case value == "":
+		case field.Name == "":
+			args = append(args, strings.Fields(value)...)
+		default:
+			args = append(args, field.Name+"="+value)
+		}
+	}
+
+	runInFocusedTerminal("start", args...)
+}
+
+//for backspace (chars are typed as runes, which may take several bytes)
+func withoutLastRune(s string) string {
+	_, size := utf8.DecodeLastRuneInString(s)
+	return s[:len(s)-size]
+}
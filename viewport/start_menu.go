@@ -0,0 +1,452 @@
+/*
+
+THE START MENU IS REBUILT WHENEVER THE CONFIG GETS (RE)LOADED OR THE
+RUNNING APPS CHANGE.  APPS ARE IN SUBMENUS BY THEIR CATEGORY, & ONCE IT'S
+OPEN, THE ARROW KEYS & ENTER GET AROUND IT, & TYPING FILTERS IT
+
+*/
+
+package viewport
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/skycoin/viscript/app"
+	"github.com/skycoin/viscript/config"
+	"github.com/skycoin/viscript/hypervisor"
+	"github.com/skycoin/viscript/hypervisor/input/mouse"
+	"github.com/skycoin/viscript/msg"
+	"github.com/skycoin/viscript/viewport/gl"
+	t "github.com/skycoin/viscript/viewport/terminal"
+)
+
+const (
+	START_MENU_OPTION_Divider     = "----------------"
+	START_MENU_OPTION_Defocus     = "Defocus"
+	START_MENU_OPTION_NewTerminal = "New Terminal"
+	START_MENU_OPTION_Running     = "Running apps"
+
+	startMenuSubmenuMark = " >"
+)
+
+var (
+	startMenuOpen       bool
+	startMenu           []*StartMenuEntry //top level, unfiltered
+	startMenuFiltered   []*StartMenuEntry //top level while there's a filter
+	startMenuFilter     string            //typed while it's open
+	startMenuPath       []int             //selected entry of the top level, then of its submenu (if entered)
+	startMenuGeneration int               //of the config it was built from
+	startMenuRunning    string            //ids of the running apps it was built with
+)
+
+type StartMenuEntry struct {
+	MenuOption
+	Run     func() //nil for dividers & submenus
+	Submenu []*StartMenuEntry
+}
+
+func openStartMenu(byKeyboard bool) {
+	startMenuOpen = true
+	startMenuFilter = ""
+	startMenuPath = nil
+	rebuildStartMenuIfNeeded()
+
+	if byKeyboard {
+		startMenuPath = []int{nextSelectable(startMenuColumns()[0], -1, 1)}
+	}
+
+	highlightStartMenu()
+}
+
+func closeStartMenu() {
+	startMenuOpen = false
+	startMenuFilter = ""
+	startMenuPath = nil
+}
+
+func drawStartMenu() {
+	if !startMenuOpen {
+		return
+	}
+
+	rebuildStartMenuIfNeeded()
+	setStartMenuBounds()
+
+	for _, column := range startMenuColumns() {
+		drawMenuOptions(startMenuOptions(column))
+	}
+}
+
+//selects what the pointer is over (opening its submenu, if it has one)
+func highlightStartMenuUnderPointer() {
+	if col, i := startMenuEntryUnderPointer(); col >= 0 {
+		if col == 0 {
+			startMenuPath = []int{i}
+		} else {
+			startMenuPath = []int{startMenuPath[0], i}
+		}
+
+		highlightStartMenu()
+	}
+}
+
+//returns whether it's still open (clicking a submenu's entry opens it,
+//anything else closes the menu, running what was clicked on)
+func onStartMenuClick() bool {
+	col, i := startMenuEntryUnderPointer()
+
+	if col >= 0 {
+		entry := startMenuColumns()[col][i]
+
+		if len(entry.Submenu) > 0 {
+			return true
+		}
+
+		if entry.Run != nil {
+			closeStartMenu()
+			entry.Run()
+			return false
+		}
+	}
+
+	closeStartMenu()
+	return false
+}
+
+//returns whether the key was used up
+func onStartMenuKey(m msg.MessageKey) bool {
+	if msg.Action(m.Action) == msg.Release {
+		return false
+	}
+
+	columns := startMenuColumns()
+	level := len(startMenuPath) - 1
+
+	switch m.Key {
+
+	case msg.KeyEscape:
+		if startMenuFilter != "" {
+			setStartMenuFilter("")
+		} else {
+			closeStartMenu()
+		}
+
+	case msg.KeyUp, msg.KeyDown:
+		delta := 1
+		if m.Key == msg.KeyUp {
+			delta = -1
+		}
+
+		if level < 0 {
+			startMenuPath = []int{nextSelectable(columns[0], -1, delta)}
+		} else {
+			startMenuPath[level] = nextSelectable(columns[level], startMenuPath[level], delta)
+		}
+
+	case msg.KeyRight:
+		enterStartMenuSubmenu()
+
+	case msg.KeyLeft:
+		if level > 0 {
+			startMenuPath = startMenuPath[:1]
+		}
+
+	case msg.KeyEnter, msg.KeyKPEnter:
+		if level < 0 {
+			break
+		}
+
+		entry := columns[level][startMenuPath[level]]
+
+		if entry.Run != nil {
+			closeStartMenu()
+			entry.Run()
+			return true
+		}
+
+		enterStartMenuSubmenu()
+
+	case msg.KeyBackspace:
+		if len(startMenuFilter) > 0 {
+			setStartMenuFilter(withoutLastRune(startMenuFilter))
+		}
+
+	default:
+		return false
+
+	}
+
+	highlightStartMenu()
+	return true
+}
+
+//typing filters it
+func onStartMenuChar(m msg.MessageChar) {
+	if m.Char >= ' ' {
+		setStartMenuFilter(startMenuFilter + string(rune(m.Char)))
+	}
+}
+
+//
+//
+//private
+//
+//
+
+func rebuildStartMenuIfNeeded() {
+	ids := runningAppIds()
+	running := fmt.Sprint(ids)
+
+	if len(startMenu) > 0 &&
+		startMenuGeneration == config.Generation() &&
+		startMenuRunning == running {
+		return
+	}
+
+	startMenuGeneration = config.Generation()
+	startMenuRunning = running
+	startMenu = []*StartMenuEntry{}
+
+	//a submenu for each category of apps
+	categories := config.AppsByCategory()
+	names := []string{}
+
+	for category := range categories {
+		names = append(names, category)
+	}
+
+	sort.Strings(names)
+
+	for _, category := range names {
+		submenu := []*StartMenuEntry{}
+
+		for _, appName := range categories[category] {
+			appName := appName
+			submenu = append(submenu, newStartMenuEntry("Start "+appName, func() {
+				openParamPrompt(appName)
+			}))
+		}
+
+		startMenu = append(startMenu, &StartMenuEntry{
+			MenuOption: MenuOption{Name: category + startMenuSubmenuMark},
+			Submenu:    submenu})
+	}
+
+	//& one for attaching the running apps
+	if len(ids) > 0 {
+		submenu := []*StartMenuEntry{}
+
+		for _, id := range ids {
+			ea := hypervisor.GlobalRunningExternalApps.TaskMap[msg.ExternalAppId(id)]
+			arg := strconv.Itoa(id)
+
+			submenu = append(submenu, newStartMenuEntry(
+				fmt.Sprintf("Attach %d (%s)", id, ea.GetFullCommandLine()), func() {
+					runInFocusedTerminal("attach", arg)
+				}))
+		}
+
+		startMenu = append(startMenu, &StartMenuEntry{
+			MenuOption: MenuOption{Name: START_MENU_OPTION_Running + startMenuSubmenuMark},
+			Submenu:    submenu})
+	}
+
+	startMenu = append(startMenu,
+		newStartMenuEntry(START_MENU_OPTION_Divider, nil),
+		newStartMenuEntry(START_MENU_OPTION_Defocus, func() {
+			t.Terms.Defocus()
+		}),
+		newStartMenuEntry(START_MENU_OPTION_NewTerminal, func() {
+			t.Terms.Add()
+		}))
+
+	setStartMenuFilter(startMenuFilter)
+}
+
+func newStartMenuEntry(name string, run func()) *StartMenuEntry {
+	return &StartMenuEntry{MenuOption: MenuOption{Name: name}, Run: run}
+}
+
+//a flat list of whatever can be run whose name contains it (under a line showing it)
+func setStartMenuFilter(filter string) {
+	startMenuFilter = filter
+	startMenuPath = nil
+	startMenuFiltered = nil
+
+	if filter == "" {
+		highlightStartMenu()
+		return
+	}
+
+	matches := []*StartMenuEntry{}
+	var add func(entries []*StartMenuEntry)
+
+	add = func(entries []*StartMenuEntry) {
+		for _, entry := range entries {
+			add(entry.Submenu)
+
+			if entry.Run != nil &&
+				strings.Contains(strings.ToLower(entry.Name), strings.ToLower(filter)) {
+
+				matches = append(matches, entry)
+			}
+		}
+	}
+
+	add(startMenu)
+	header := "Filter: " + filter
+
+	if len(matches) == 0 {
+		header += " (no matches)"
+	}
+
+	startMenuFiltered = append([]*StartMenuEntry{newStartMenuEntry(header, nil)}, matches...)
+
+	if len(matches) > 0 {
+		startMenuPath = []int{1}
+	}
+
+	highlightStartMenu()
+}
+
+//the top level, & the submenu of its selected entry (if it has one)
+func startMenuColumns() [][]*StartMenuEntry {
+	top := startMenu
+	if startMenuFilter != "" {
+		top = startMenuFiltered
+	}
+
+	columns := [][]*StartMenuEntry{top}
+
+	if len(startMenuPath) > 0 {
+		if submenu := top[startMenuPath[0]].Submenu; len(submenu) > 0 {
+			columns = append(columns, submenu)
+		}
+	}
+
+	return columns
+}
+
+func startMenuOptions(entries []*StartMenuEntry) []*MenuOption {
+	options := []*MenuOption{}
+
+	for _, entry := range entries {
+		options = append(options, &entry.MenuOption)
+	}
+
+	return options
+}
+
+//what's on the path is lit up
+func highlightStartMenu() {
+	for _, entries := range [][]*StartMenuEntry{startMenu, startMenuFiltered} {
+		for _, entry := range entries {
+			entry.Highlighted = false
+
+			for _, sub := range entry.Submenu {
+				sub.Highlighted = false
+			}
+		}
+	}
+
+	for level, column := range startMenuColumns() {
+		if level < len(startMenuPath) {
+			column[startMenuPath[level]].Highlighted = true
+		}
+	}
+}
+
+func enterStartMenuSubmenu() {
+	columns := startMenuColumns()
+
+	if len(startMenuPath) == 1 && len(columns) > 1 {
+		startMenuPath = append(startMenuPath, nextSelectable(columns[1], -1, 1))
+	}
+}
+
+//the next one from i (wrapping around), skipping dividers & such
+func nextSelectable(entries []*StartMenuEntry, i, delta int) int {
+	for range entries {
+		i = (i + delta + len(entries)) % len(entries)
+
+		if entries[i].Run != nil || len(entries[i].Submenu) > 0 {
+			return i
+		}
+	}
+
+	return 0
+}
+
+//column & index (-1, -1 when over none)
+func startMenuEntryUnderPointer() (int, int) {
+	for col, column := range startMenuColumns() {
+		for i, entry := range column {
+			if entry.Bounds != nil && mouse.PointerIsInside(entry.Bounds) &&
+				(entry.Run != nil || len(entry.Submenu) > 0) {
+
+				return col, i
+			}
+		}
+	}
+
+	return -1, -1
+}
+
+//the top level rises from the start button, & a submenu hangs
+//down from its entry (but stays above the taskbar)
+func setStartMenuBounds() {
+	columns := startMenuColumns()
+	bottom := -gl.CanvasExtents.Y + app.TaskBarHeight
+	left := -gl.CanvasExtents.X
+	width := menuWidth(startMenuOptions(columns[0]))
+	y := bottom
+
+	//start with the last option, which borders the taskbar
+	for i := len(columns[0]) - 1; i >= 0; i-- {
+		columns[0][i].Bounds = &app.Rectangle{y + app.TaskBarHeight, left + width, y, left}
+		y += app.TaskBarHeight
+	}
+
+	if len(columns) < 2 {
+		return
+	}
+
+	submenu := columns[1]
+	left += width
+	width = menuWidth(startMenuOptions(submenu))
+	top := columns[0][startMenuPath[0]].Bounds.Top
+
+	if top-float32(len(submenu))*app.TaskBarHeight < bottom {
+		top = bottom + float32(len(submenu))*app.TaskBarHeight
+	}
+
+	for _, entry := range submenu {
+		entry.Bounds = &app.Rectangle{top, left + width, top - app.TaskBarHeight, left}
+		top -= app.TaskBarHeight
+	}
+}
+
+func runningAppIds() []int {
+	ids := []int{}
+
+	for id := range hypervisor.GlobalRunningExternalApps.TaskMap {
+		ids = append(ids, int(id))
+	}
+
+	sort.Ints(ids)
+	return ids
+}
+
+//(making one, if the desktop is empty)
+func runInFocusedTerminal(command string, args ...string) {
+	if t.Terms.GetFocusedTerminal() == nil {
+		t.Terms.Add()
+	}
+
+	m := msg.Serialize(msg.TypeTokenizedCommand,
+		msg.MessageTokenizedCommand{Command: command, Args: args})
+	t.Terms.GetFocusedTerminal().RelayToTask(m)
+}
@@ -1,31 +1,13 @@
-/*
-
-IF START BUTTON EVER NEEDS TO CHANGE ITS OPTIONS AT RUNTIME,
-THESE "STARTMENU" IDENTIFIERS (and all related code)
-WILL NEED TO EXAMINED & EXTENDED
-
-*/
-
 package viewport
 
 import (
 	"github.com/skycoin/viscript/app"
-	"github.com/skycoin/viscript/config"
 	"github.com/skycoin/viscript/viewport/gl"
 	"github.com/skycoin/viscript/viewport/terminal"
 )
 
-const (
-	START_MENU_OPTION_Divider     = "----------------"
-	START_MENU_OPTION_Defocus     = "Defocus"
-	START_MENU_OPTION_NewTerminal = "New Terminal"
-)
-
 var (
-	startMenuOpen          bool
-	startMenuOptionNameMax int //the longest option label/text
-	startMenu              []*MenuOption
-	previousCanvasExtents  app.Vec2F
+	previousCanvasExtents app.Vec2F
 	//current (iterators)
 	buttonBounds *app.Rectangle
 	charBounds   *app.Rectangle
@@ -38,17 +20,15 @@ type MenuOption struct {
 }
 
 func drawTaskBarStartButtonAndMenu() {
-	gl.SetColor(gl.Gray)
-
 	if previousCanvasExtents != gl.CanvasExtents {
 		previousCanvasExtents = gl.CanvasExtents
-		setOptionButtonsBounds()
 		terminal.Terms.SetTaskBarButtonBounds()
 	}
 
 	drawTaskBarBackground()
 	drawStartButton()
 	drawTaskBarTerminalButtons()
+	drawTray()
 	drawStartMenu()
 }
 
@@ -59,6 +39,7 @@ func drawTaskBarBackground() {
 		-gl.CanvasExtents.Y,
 		-gl.CanvasExtents.X}
 
+	gl.SetColor(gl.Theme.TaskbarBackground[:])
 	gl.Draw9SlicedRect(
 		gl.Pic_GradientBorder,
 		buttonBounds,
@@ -66,9 +47,8 @@ func drawTaskBarBackground() {
 }
 
 func drawStartButton() {
-	if startMenuOpen {
-		gl.SetColor(gl.White)
-	}
+	background, text := gl.TaskbarColors(startMenuOpen)
+	gl.SetColor(background)
 
 	//now make buttons inset from task bar
 	buttonBounds.Top -= app.TaskBarBorderSpan
@@ -85,6 +65,7 @@ func drawStartButton() {
 	buttonBounds.Left += buttonBounds.Width()
 	buttonBounds.Right += buttonBounds.Width()
 
+	gl.SetColor(text)
 	gl.Draw9SlicedRect(
 		gl.Pic_TriangleUp,
 		charBounds,
@@ -94,119 +75,137 @@ func drawStartButton() {
 	charBounds.Right += app.TaskBarCharWid
 }
 
-func drawStartMenu() {
-	if startMenuOpen {
-		setupMenuIfNeeded()
+//(each a button with a line of text, lit up when the pointer is over it)
+func drawMenuOptions(options []*MenuOption) {
+	for _, option := range options {
+		background, text := gl.TaskbarColors(option.Highlighted)
+		gl.SetColor(background)
 
-		for _, option := range startMenu {
-			if option.Highlighted {
-				gl.SetColor(gl.White)
+		//draw option background
+		gl.Draw9SlicedRect(
+			gl.Pic_GradientBorder,
+			option.Bounds,
+			app.TaskBarDepth)
 
-			} else {
-				gl.SetColor(gl.Gray)
-			}
+		//draw text
+		gl.SetColor(text)
+		charBounds = getCharBoundsInsetFrom(option.Bounds)
+		charBounds.Right = charBounds.Left + app.TaskBarCharWid
 
-			//draw option background
-			gl.Draw9SlicedRect(
-				gl.Pic_GradientBorder,
-				option.Bounds,
-				app.TaskBarDepth)
+		for i := 0; i < len(option.Name); i++ {
+			gl.DrawCharAtRect(rune(option.Name[i]), charBounds, app.TaskBarDepth)
+			charBounds.Left += app.TaskBarCharWid
+			charBounds.Right += app.TaskBarCharWid
+		}
+	}
+}
 
-			//draw text
-			charBounds = getCharBoundsInsetFrom(option.Bounds)
-			charBounds.Right = charBounds.Left + app.TaskBarCharWid
+//wide enough for the longest option
+func menuWidth(options []*MenuOption) float32 {
+	widest := 0
 
-			for i := 0; i < len(option.Name); i++ {
-				gl.DrawCharAtRect(rune(option.Name[i]), charBounds, app.TaskBarDepth)
-				charBounds.Left += app.TaskBarCharWid
-				charBounds.Right += app.TaskBarCharWid
-			}
+	for _, option := range options {
+		if widest < len(option.Name) {
+			widest = len(option.Name)
 		}
 	}
+
+	return float32(widest)*app.TaskBarCharWid + app.TaskBarBorderSpan*2
+}
+
+//stacked in the middle of the canvas (for prompts)
+func centerMenuOptions(options []*MenuOption) {
+	width := menuWidth(options)
+	y := app.TaskBarHeight * float32(len(options)) / 2
+
+	for _, option := range options {
+		option.Bounds = &app.Rectangle{y, width / 2, y - app.TaskBarHeight, -width / 2}
+		y -= app.TaskBarHeight
+	}
 }
 
-func setupMenuIfNeeded() {
-	if len(startMenu) < 1 {
-		for name, _ := range config.Global.Apps {
-			startMenu = append(startMenu, &MenuOption{Name: "Start " + name})
+func drawTaskBarTerminalButtons() {
+	foc := terminal.Terms.GetFocusedWindow()
+
+	//workspace switcher
+	for _, ws := range terminal.Terms.Workspaces {
+		drawTaskBarTerminalButton(ws.TaskBarButton, ws.Name, ws == terminal.Terms.ActiveWorkspace, false)
+	}
+
+	for _, w := range terminal.Terms.Visible() {
+		button := *w.TaskBarButton
+
+		//several tabs sit inset, within a button for their window
+		if len(w.Tabs) > 1 {
+			background, _ := gl.TaskbarColors(false)
+			gl.SetColor(background)
+			gl.Draw9SlicedRect(gl.Pic_GradientBorder, &button, app.TaskBarDepth)
 		}
 
-		startMenu = append(startMenu, &MenuOption{Name: START_MENU_OPTION_Divider})
-		startMenu = append(startMenu, &MenuOption{Name: START_MENU_OPTION_Defocus})
-		startMenu = append(startMenu, &MenuOption{Name: START_MENU_OPTION_NewTerminal})
+		for i, tab := range w.Tabs {
+			button = *tab.TaskBarButton
 
-		//ensure longest label length is stored
-		for _, option := range startMenu {
-			if startMenuOptionNameMax < len(option.Name) {
-				startMenuOptionNameMax = len(option.Name)
+			if len(w.Tabs) > 1 {
+				button.Top -= app.TaskBarBorderSpan / 2
+				button.Bottom += app.TaskBarBorderSpan / 2
+				button.Left += app.TaskBarBorderSpan / 2
+				button.Right -= app.TaskBarBorderSpan / 2
 			}
-		}
 
-		setOptionButtonsBounds()
+			drawTaskBarTerminalButton(&button, tab.Active().TaskBarButtonText, w == foc && i == w.ActiveTab, w.Minimized)
+		}
 	}
 }
 
-func setOptionButtonsBounds() {
-	y := -gl.CanvasExtents.Y + app.TaskBarHeight
-	left := -gl.CanvasExtents.X
-	right := float32(startMenuOptionNameMax) * app.TaskBarCharWid
-	right += left + app.TaskBarBorderSpan*2
+func drawTaskBarTerminalButton(button *app.Rectangle, buttonText string, active, dimmed bool) {
+	charBounds.Left = button.Left + app.TaskBarBorderSpan
+	charBounds.Right = charBounds.Left + app.TaskBarCharWid
 
-	//start with the last option, which borders the taskbar
-	for i := len(startMenu) - 1; i >= 0; i-- {
-		startMenu[i].Bounds = &app.Rectangle{y + app.TaskBarHeight, right, y, left}
-		y += app.TaskBarHeight
-	}
-}
+	background, text := gl.TaskbarColors(active)
 
-func drawTaskBarTerminalButtons() {
-	for _, term := range terminal.Terms.TermMap {
-		charBounds.Left = term.TaskBarButton.Left + app.TaskBarBorderSpan
-		charBounds.Right = charBounds.Left + app.TaskBarCharWid
+	if dimmed { //(minimized)
+		background, text = gl.Dimmed(background), gl.Dimmed(text)
+	}
 
-		if term.TerminalId == terminal.Terms.FocusedId {
-			gl.SetColor(gl.White)
-		} else {
-			gl.SetColor(gl.Gray)
-		}
+	gl.SetColor(background)
 
-		//draw button background
-		gl.Draw9SlicedRect(
-			gl.Pic_GradientBorder,
-			term.TaskBarButton,
-			app.TaskBarDepth)
+	//draw button background
+	gl.Draw9SlicedRect(
+		gl.Pic_GradientBorder,
+		button,
+		app.TaskBarDepth)
 
-		//prepare for id text
-		textMax := term.TaskBarButton.Right - app.TaskBarBorderSpan
-		//when abbreviating text, append "..." chars...
-		dotWid := app.TaskBarCharWid / 2 //...but at half width
+	//prepare for id text
+	textMax := button.Right - app.TaskBarBorderSpan
+	//when abbreviating text, append "..." chars...
+	dotWid := app.TaskBarCharWid / 2 //...but at half width
 
-		if term.TaskBarButton.Width()-app.TaskBarBorderSpan*2 <
-			float32(len(term.TaskBarButtonText))*app.TaskBarCharWid {
+	if button.Width()-app.TaskBarBorderSpan*2 <
+		float32(len(buttonText))*app.TaskBarCharWid {
 
-			textMax -= (3 * dotWid)
-		}
+		textMax -= (3 * dotWid)
+	}
 
-		//draw id text
-		max := len(term.TaskBarButtonText)
-		for i := 0; i < max; i++ {
-			if charBounds.Right <= textMax {
-				gl.DrawCharAtRect(rune(term.TaskBarButtonText[i]), charBounds, app.TaskBarDepth)
-			} else { //draw 3 dots
-				charBounds.Right = charBounds.Left + dotWid
-
-				for i := 0; i < 3; i++ {
-					gl.DrawCharAtRect('.', charBounds, app.TaskBarDepth)
-					charBounds.Left += dotWid
-					charBounds.Right += dotWid
-				}
-
-				break
+	//draw id text
+	gl.SetColor(text)
+	max := len(buttonText)
+	for i := 0; i < max; i++ {
+		if charBounds.Right <= textMax {
+			gl.DrawCharAtRect(rune(buttonText[i]), charBounds, app.TaskBarDepth)
+		} else { //draw 3 dots
+			charBounds.Right = charBounds.Left + dotWid
+
+			for i := 0; i < 3; i++ {
+				gl.DrawCharAtRect('.', charBounds, app.TaskBarDepth)
+				charBounds.Left += dotWid
+				charBounds.Right += dotWid
 			}
 
-			charBounds.Left += app.TaskBarCharWid
-			charBounds.Right += app.TaskBarCharWid
+			break
 		}
+
+		charBounds.Left += app.TaskBarCharWid
+		charBounds.Right += app.TaskBarCharWid
 	}
 }
 
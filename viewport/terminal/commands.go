@@ -1,26 +1,52 @@
 package terminal
 
 import (
+	"errors"
 	"github.com/skycoin/viscript/msg"
 	"strconv"
+	"strings"
 )
 
+//set by the viewport, which asks what to do with an attached app 1st
+//(see viewport/close_prompt.go).  when nil, apps get detached
+var CloseTerminals func(terms []*Terminal)
+
 func (ts *TerminalStack) onUserCommandFinalStage(commander msg.TerminalId, cmd msg.MessageTokenizedCommand) {
 	switch cmd.Command {
 
+	case "always_on_top":
+		fallthrough
+	case "lower":
+		fallthrough
+	case "raise":
+		ts.commandStacking(commander, cmd)
 	case "close_term":
 		fallthrough
 	case "focus":
 		ts.onGivenTerminalId(commander, cmd)
 	case "defocus":
 		ts.Defocus()
+	case "layout":
+		ts.commandLayout(commander, cmd)
 	case "list_terms":
 		ts.commandListTerminals(commander, cmd)
+	case "move_to_workspace":
+		ts.commandMoveToWorkspace(commander, cmd)
 	case "new_term":
 		//temporary
 		//for now, we'll be testing the difference between fixed size and dynamic terminals.
 		//the 1st/initial terminal will be dynamic.  new terms afterwards will all be fixed.
 		ts.AddWithFixedSizeState(true)
+	case "rename_term":
+		ts.commandRenameTerminal(commander, cmd)
+	case "new_tab":
+		if cmdTerm := ts.GetTerminal(commander); cmdTerm != nil {
+			ts.AddTab(cmdTerm.Window)
+		}
+	case "split":
+		ts.commandSplit(commander, cmd)
+	case "workspace":
+		ts.commandWorkspace(commander, cmd)
 	default:
 		println("onUserCommandFinalStage()   UNHANDLED COMMAND!!!:", cmd.Command)
 		println("onUserCommandFinalStage()   UNHANDLED COMMAND!!!:", cmd.Command)
@@ -38,15 +64,47 @@ func (ts *TerminalStack) onUserCommandFinalStage(commander msg.TerminalId, cmd m
 
 func (ts *TerminalStack) onGivenTerminalId(commander msg.TerminalId, cmd msg.MessageTokenizedCommand) {
 	matchId := msg.TerminalId(0)
-	var cmdTerm *Terminal
+	cmdTerm := ts.GetTerminal(commander)
+
+	if match := ts.findTerminal(cmd.Args[0]); match != nil {
+		matchId = match.TerminalId
+	}
+
+	//set new focus (or show error)
+	if matchId != 0 {
+		println("finalStage   -   Found terminal starting with:", cmd.Args[0])
+
+		switch cmd.Command {
+
+		case "close_term": //(even the last one, leaving an empty desktop)
+			if CloseTerminals != nil {
+				CloseTerminals([]*Terminal{ts.GetTerminal(matchId)})
+			} else {
+				ts.GetTerminal(matchId).DetachExternalApp()
+				ts.Remove(matchId)
+			}
+
+		case "focus":
+			ts.SetFocused(matchId)
 
-	for _, t := range ts.TermMap {
-		if t.TerminalId == commander {
-			cmdTerm = t
 		}
+	} else {
+		s := "ERROR!!!  \"" + cmd.Args[0] + "\" is not the name or beginning of any Terminal id."
+		println(s)
+		cmdTerm.PutString(s)
+		cmdTerm.NewLine()
+	}
+}
 
+//the terminal of the name given, or else the 1st whose id starts with the digits given
+func (ts *TerminalStack) findTerminal(arg string) *Terminal {
+	if t := ts.getTerminalByName(arg); t != nil {
+		return t
+	}
+
+	for _, t := range ts.TermMap {
 		ruledOutMatch := false
-		arg := cmd.Args[0]
+		arg := arg
 		tId := strconv.Itoa(int(t.TerminalId))
 
 		//chop runes off the end
@@ -64,48 +122,186 @@ func (ts *TerminalStack) onGivenTerminalId(commander msg.TerminalId, cmd msg.Mes
 		}
 
 		if !ruledOutMatch {
-			matchId = t.TerminalId
-			break
+			return t
 		}
 	}
 
-	//set new focus (or show error)
-	if matchId != 0 {
-		println("finalStage   -   Found terminal starting with:", cmd.Args[0])
+	return nil
+}
 
-		switch cmd.Command {
+func (ts *TerminalStack) commandListTerminals(commander msg.TerminalId, cmd msg.MessageTokenizedCommand) {
+	var m msg.MessageTerminalIds
+	m.Focused = commander
 
-		case "close_term":
-			if len(ts.TermMap) < 2 {
-				s := "Shouldn't close when only 1 terminal remains (UNTIL GUI IS MADE)."
-				println(s)
-				//st.PrintError("Shouldn't close when only 1 terminal remains (UNTIL GUI IS MADE)")
-				cmdTerm.PutString(s)
-				cmdTerm.NewLine()
-				return
-			}
+	for _, term := range ts.TermMap {
+		m.TermIds = append(m.TermIds, term.TerminalId)
+		m.Labels = append(m.Labels, term.labelAndTitle())
+	}
 
-			ts.Remove(matchId)
+	if cmdTerm := ts.GetTerminal(commander); cmdTerm != nil {
+		cmdTerm.RelayToTask(msg.Serialize(msg.TypeTerminalIds, m))
+	}
+}
 
-		case "focus":
-			ts.SetFocused(matchId)
+func (ts *TerminalStack) commandLayout(commander msg.TerminalId, cmd msg.MessageTokenizedCommand) {
+	cmdTerm := ts.GetTerminal(commander)
+	s := "Layout: " + ts.LayoutOrDefault() + "   (" + strings.Join(Layouts, ", ") + ")"
+
+	if len(cmd.Args) > 0 {
+		s = "Layout: " + strings.ToLower(cmd.Args[0])
 
+		if err := ts.SetLayout(cmd.Args[0]); err != nil {
+			s = "ERROR!!!  " + err.Error()
 		}
-	} else {
-		s := "ERROR!!!  \"" + cmd.Args[0] + "\" is not the beginning of any Terminal id."
-		println(s)
+	}
+
+	if cmdTerm != nil {
 		cmdTerm.PutString(s)
 		cmdTerm.NewLine()
 	}
 }
 
-func (ts *TerminalStack) commandListTerminals(commander msg.TerminalId, cmd msg.MessageTokenizedCommand) {
-	var m msg.MessageTerminalIds
-	m.Focused = commander
+func (ts *TerminalStack) commandSplit(commander msg.TerminalId, cmd msg.MessageTokenizedCommand) {
+	cmdTerm := ts.GetTerminal(commander)
+	sideBySide := true
 
-	for _, term := range ts.TermMap {
-		m.TermIds = append(m.TermIds, term.TerminalId)
+	if len(cmd.Args) > 0 {
+		switch strings.ToLower(cmd.Args[0]) {
+		case "h":
+		case "v":
+			sideBySide = false
+		default:
+			cmdTerm.PutString("ERROR!!!  \"" + cmd.Args[0] + "\" isn't a direction (use h or v)")
+			cmdTerm.NewLine()
+			return
+		}
+	}
+
+	if _, err := ts.Split(commander, sideBySide); err != nil && cmdTerm != nil {
+		cmdTerm.PutString("ERROR!!!  " + err.Error())
+		cmdTerm.NewLine()
+	}
+}
+
+func (ts *TerminalStack) commandWorkspace(commander msg.TerminalId, cmd msg.MessageTokenizedCommand) {
+	cmdTerm := ts.GetTerminal(commander)
+	names := []string{}
+
+	for _, ws := range ts.Workspaces {
+		names = append(names, ws.Name)
+	}
+
+	s := "Workspace: " + ts.ActiveWorkspace.Name + "   (" + strings.Join(names, ", ") + ")"
+
+	if len(cmd.Args) > 0 {
+		s = "Workspace: " + cmd.Args[0]
+
+		if err := ts.SwitchWorkspace(cmd.Args[0]); err != nil {
+			s = "ERROR!!!  " + err.Error()
+		}
+	}
+
+	if cmdTerm != nil {
+		cmdTerm.PutString(s)
+		cmdTerm.NewLine()
+	}
+}
+
+func (ts *TerminalStack) commandMoveToWorkspace(commander msg.TerminalId, cmd msg.MessageTokenizedCommand) {
+	cmdTerm := ts.GetTerminal(commander)
+	if cmdTerm == nil {
+		return
+	}
+
+	s := "Moved to workspace: "
+	err := errors.New("which workspace? (e.g. \"move_to_workspace web\")")
+
+	if len(cmd.Args) > 0 {
+		s += cmd.Args[0]
+		err = ts.MoveToWorkspace(cmdTerm.Window, cmd.Args[0])
+	}
+
+	if err != nil {
+		s = "ERROR!!!  " + err.Error()
+	}
+
+	cmdTerm.PutString(s)
+	cmdTerm.NewLine()
+}
+
+//raise, lower & always_on_top (toggled) act on the window
+//of the terminal given, or else of the commander
+func (ts *TerminalStack) commandStacking(commander msg.TerminalId, cmd msg.MessageTokenizedCommand) {
+	cmdTerm := ts.GetTerminal(commander)
+	if cmdTerm == nil {
+		return
+	}
+
+	target := cmdTerm
+
+	if len(cmd.Args) > 0 {
+		target = ts.findTerminal(cmd.Args[0])
+
+		if target == nil {
+			cmdTerm.PutString("ERROR!!!  \"" + cmd.Args[0] + "\" is not the name or beginning of any Terminal id.")
+			cmdTerm.NewLine()
+			return
+		}
+	}
+
+	switch cmd.Command {
+
+	case "always_on_top":
+		ts.SetAlwaysOnTop(target.Window, !target.Window.AlwaysOnTop)
+		s := "Always on top: off"
+
+		if target.Window.AlwaysOnTop {
+			s = "Always on top: on"
+		}
+
+		cmdTerm.PutString(s)
+		cmdTerm.NewLine()
+
+	case "lower":
+		ts.Lower(target.Window)
+
+	case "raise":
+		ts.Raise(target.Window)
+
+	}
+}
+
+//"rename_term <name>" renames the commander, "rename_term <id or name> <name>" another
+func (ts *TerminalStack) commandRenameTerminal(commander msg.TerminalId, cmd msg.MessageTokenizedCommand) {
+	cmdTerm := ts.GetTerminal(commander)
+	if cmdTerm == nil {
+		return
+	}
+
+	target := cmdTerm
+	args := cmd.Args
+
+	if len(args) > 1 {
+		target = ts.findTerminal(args[0])
+		args = args[1:]
+
+		if target == nil {
+			cmdTerm.PutString("ERROR!!!  \"" + cmd.Args[0] + "\" is not the name or beginning of any Terminal id.")
+			cmdTerm.NewLine()
+			return
+		}
+	}
+
+	s := "ERROR!!!  which name? (e.g. \"rename_term web\")"
+
+	if len(args) > 0 {
+		s = "Renamed to: " + args[0]
+
+		if err := ts.Rename(target, args[0]); err != nil {
+			s = "ERROR!!!  " + err.Error()
+		}
 	}
 
-	ts.GetFocusedTerminal().RelayToTask(msg.Serialize(msg.TypeTerminalIds, m))
+	cmdTerm.PutString(s)
+	cmdTerm.NewLine()
 }
@@ -34,56 +34,29 @@ func (ts *TerminalStack) DrawTextMode() { //for running headless mode
 }
 
 func (ts *TerminalStack) Draw() {
-	for _, t := range ts.TermMap {
-		z := t.Depth
+	for _, w := range ts.Stacked() {
+		z := w.Depth
+		focused := w == ts.GetFocusedWindow()
+		terms := w.Terms()
 
-		if t.TerminalId == ts.FocusedId {
-			gl.SetColor(gl.White)
-		} else {
-			gl.SetColor(gl.Gray)
+		for i := range w.Tabs {
+			drawIdTab(w, i, z, focused && i == w.ActiveTab)
 		}
 
-		drawIdTab(t, z)
-
-		//main window background
-		gl.Draw9SlicedRect(gl.Pic_GradientBorder, t.Bounds, z)
-
-		//current rect (in character grid of main window)
-		cr := &app.Rectangle{
-			t.Bounds.Top,
-			t.Bounds.Left + t.CharSize.X,
-			t.Bounds.Top - t.CharSize.Y,
-			t.Bounds.Left}
+		//main window frame
+		gl.SetColor(gl.FrameColor(focused))
+		gl.Draw9SlicedRect(gl.Pic_GradientBorder, w.Bounds, z)
 
-		cr.Left += t.BorderSize //start with the initial character grid rect being offset by the border margin
-		cr.Right += t.BorderSize
-		cr.Top -= t.BorderSize
-		cr.Bottom -= t.BorderSize
-
-		for x := 0; x < t.GridSize.X; x++ {
-			for y := 0; y < t.GridSize.Y; y++ {
-				if t.Chars[y][x] != 0 {
-					gl.DrawCharAtRect(rune(t.Chars[y][x]), cr, z)
-				}
-
-				//draw cursor (if it's here)
-				if x == int(t.Cursor.X) &&
-					y == int(t.Cursor.Y) {
-
-					gl.DrawQuad(
-						gl.Pic_GradientBorder,
-						gl.Curs.GetCurrentFrame(*cr), z)
-				}
+		for _, t := range terms {
+			paneFocused := t.TerminalId == ts.FocusedId
 
-				cr.Top -= t.CharSize.Y
-				cr.Bottom -= t.CharSize.Y
+			//panes of a split window get frames of their own, showing which has focus
+			if len(terms) > 1 {
+				gl.SetColor(gl.FrameColor(paneFocused))
+				gl.Draw9SlicedRect(gl.Pic_GradientBorder, t.Bounds, z)
 			}
 
-			cr.Top = t.Bounds.Top - t.BorderSize
-			cr.Bottom = t.Bounds.Top - t.BorderSize - t.CharSize.Y
-
-			cr.Left += t.CharSize.X
-			cr.Right += t.CharSize.X
+			drawPane(t, z, paneFocused)
 		}
 	}
 }
@@ -94,39 +67,123 @@ func (ts *TerminalStack) Draw() {
 //
 //
 
-func drawIdTab(t *Terminal, z float32) {
+//background (when it's not the same color as the frame), scrollbar, chars & cursor
+func drawPane(t *Terminal, z float32, focused bool) {
+	if !gl.SameColor(gl.FrameColor(focused), gl.WindowColor(focused)) {
+		gl.SetColor(gl.WindowColor(focused))
+		gl.Draw9SlicedRect(gl.Pic_GradientBorder, &app.Rectangle{
+			t.Bounds.Top - t.BorderSize,
+			t.Bounds.Right - t.BorderSize,
+			t.Bounds.Bottom + t.BorderSize,
+			t.Bounds.Left + t.BorderSize}, z)
+	}
+
+	if thumb := t.ScrollThumbBounds(); thumb != nil {
+		gl.SetColor(gl.Dimmed(gl.FrameColor(focused)))
+		gl.Draw9SlicedRect(gl.Pic_GradientBorder, t.ScrollBarBounds(), z)
+		gl.SetColor(gl.FrameColor(focused))
+		gl.Draw9SlicedRect(gl.Pic_GradientBorder, thumb, z)
+	}
+
+	//current rect (in character grid of main window)
+	cr := &app.Rectangle{
+		t.Bounds.Top,
+		t.Bounds.Left + t.CharSize.X,
+		t.Bounds.Top - t.CharSize.Y,
+		t.Bounds.Left}
+
+	cr.Left += t.BorderSize //start with the initial character grid rect being offset by the border margin
+	cr.Right += t.BorderSize
+	cr.Top -= t.BorderSize
+	cr.Bottom -= t.BorderSize
+
+	gl.SetColor(gl.TextColor(focused))
+
+	for x := 0; x < t.GridSize.X; x++ {
+		for y := 0; y < t.GridSize.Y; y++ {
+			//selected (drawn 1st, so the char covers it at the same depth)
+			if t.IsSelected(x, y) {
+				gl.SetColor(gl.Theme.Selection[:])
+				gl.DrawQuad(gl.Pic_GradientBorder, cr, z)
+				gl.SetColor(gl.TextColor(focused))
+			}
+
+			if t.Chars[y][x] != 0 {
+				gl.DrawCharAtRect(rune(t.Chars[y][x]), cr, z)
+			}
+
+			//draw cursor (if it's here)
+			if x == int(t.Cursor.X) &&
+				y == int(t.Cursor.Y) {
+
+				if frame := gl.Curs.GetCurrentFrame(*cr); frame != nil {
+					if focused {
+						gl.SetColor(gl.Theme.Cursor[:])
+					}
+
+					gl.DrawQuad(gl.Pic_GradientBorder, frame, z)
+					gl.SetColor(gl.TextColor(focused))
+				}
+			}
+
+			cr.Top -= t.CharSize.Y
+			cr.Bottom -= t.CharSize.Y
+		}
+
+		cr.Top = t.Bounds.Top - t.BorderSize
+		cr.Bottom = t.Bounds.Top - t.BorderSize - t.CharSize.Y
+
+		cr.Left += t.CharSize.X
+		cr.Right += t.CharSize.X
+	}
+}
+
+func drawIdTab(w *Window, i int, z float32, focused bool) {
 	//...with a rectangle whose bottom lip/edge will be covered by main window
 
-	tr := t.GetTabBounds() //text rectangle (but used to draw whole tab background 1st)
+	tr := w.GetTabBounds(i) //text rectangle (but used to draw whole tab background 1st)
+	cs := w.charSize
+	tabText := w.Tabs[i].Active().TabText
 
 	//id tab background
+	gl.SetColor(gl.FrameColor(focused))
 	gl.Draw9SlicedRect(gl.Pic_GradientBorder, tr, z)
 
-	//close button background
-	cbb := &app.Rectangle{
-		tr.Top,
-		tr.Right,
-		tr.Bottom,
-		tr.Right - t.CharSize.X - t.BorderSize*2}
-	gl.Draw9SlicedRect(gl.Pic_GradientBorder, cbb, z)
-
 	//push in edges to encompass ONLY the text (leaving a border visible)
-	tr.Top -= t.BorderSize
-	tr.Bottom += t.BorderSize
-	tr.Left += t.BorderSize
-	tr.Right = tr.Left + t.CharSize.X //....and shrink width to char size
+	tr.Top -= borderSize
+	tr.Bottom += borderSize
+	tr.Left += borderSize
+	tr.Right = tr.Left + cs.X //....and shrink width to char size
 
 	//draw the id #
-	max := len(t.TabText) - 2
+	gl.SetColor(gl.TextColor(focused))
+
+	for i := 0; i < len(tabText); i++ {
+		gl.DrawCharAtRect(rune(tabText[i]), tr, z)
+		tr.Left += cs.X
+		tr.Right += cs.X
+	}
+
+	//buttons
+	for b, char := range tabButtonChars(w) {
+		bb := w.GetTabButtonBounds(i, b)
+		gl.SetColor(gl.FrameColor(focused))
+		gl.Draw9SlicedRect(gl.Pic_GradientBorder, bb, z)
+
+		bb.Top -= borderSize
+		bb.Left += borderSize
+		bb.Right = bb.Left + cs.X
+		bb.Bottom = bb.Top - cs.Y
+		gl.SetColor(gl.TextColor(focused))
+		gl.DrawCharAtRect(char, bb, z)
+	}
+}
 
-	for i := 0; i < max; i++ {
-		gl.DrawCharAtRect(rune(t.TabText[i]), tr, z)
-		tr.Left += t.CharSize.X
-		tr.Right += t.CharSize.X
+//indexed by TabButton*
+func tabButtonChars(w *Window) []rune {
+	if w.Maximized {
+		return []rune{'X', 'v', '_'} //(to restore)
 	}
 
-	//close button char
-	tr.Left += t.CharSize.X
-	tr.Right += t.CharSize.X
-	gl.DrawCharAtRect('X', tr, z)
+	return []rune{'X', '^', '_'}
 }
@@ -0,0 +1,174 @@
+package terminal
+
+import (
+	"errors"
+	"math"
+	"strings"
+
+	"github.com/skycoin/viscript/app"
+	"github.com/skycoin/viscript/viewport/gl"
+)
+
+//ways of arranging terminal windows.  all but floating tile the desktop,
+//& are redone whenever windows come & go or the canvas resizes
+const (
+	LayoutFloating = "floating" //wherever they're moved to
+	LayoutGrid     = "grid"
+	LayoutMaster   = "master"  //1st terminal big on the left, the rest stacked on the right
+	LayoutSplitH   = "split_h" //side by side
+	LayoutSplitV   = "split_v" //on top of each other
+
+	masterFraction = 0.6 //of the desktop width
+)
+
+var Layouts = []string{LayoutFloating, LayoutGrid, LayoutMaster, LayoutSplitH, LayoutSplitV}
+
+func (ts *TerminalStack) SetLayout(name string) error {
+	name = strings.ToLower(name)
+
+	for _, l := range Layouts {
+		if l == name {
+			ts.Layout = name
+			ts.ApplyLayout()
+			return nil
+		}
+	}
+
+	return errors.New("\"" + name + "\" isn't a layout (use " + strings.Join(Layouts, ", ") + ")")
+}
+
+func (ts *TerminalStack) NextLayout() {
+	for i, l := range Layouts {
+		if l == ts.LayoutOrDefault() {
+			ts.SetLayout(Layouts[(i+1)%len(Layouts)])
+			return
+		}
+	}
+}
+
+func (ts *TerminalStack) LayoutOrDefault() string {
+	if ts.Layout == "" {
+		return LayoutFloating
+	}
+
+	return ts.Layout
+}
+
+//whether terminals are arranged automatically (rather than by mouse)
+func (ts *TerminalStack) IsTiled() bool {
+	return ts.LayoutOrDefault() != LayoutFloating
+}
+
+//fits every window shown in the active workspace (& the grids of its panes)
+//into its tile.  maximized ones cover the whole desktop instead, tiled or not
+func (ts *TerminalStack) ApplyLayout() {
+	windows := []*Window{}
+
+	for _, w := range shown(ts.Visible()) {
+		if w.Maximized {
+			w.fitInto(desktopArea()) //(keeping up with canvas resizes)
+		} else {
+			windows = append(windows, w)
+		}
+	}
+
+	if !ts.IsTiled() {
+		return
+	}
+
+	tiles := tilesFor(ts.LayoutOrDefault(), desktopArea(), len(windows))
+
+	for i, w := range windows {
+		w.fitInto(tiles[i])
+	}
+}
+
+//
+//
+//private
+//
+//
+
+func (ts *TerminalStack) removeWindow(w *Window) {
+	for i, ow := range ts.Windows {
+		if ow == w {
+			ts.Windows = append(ts.Windows[:i], ts.Windows[i+1:]...)
+			break
+		}
+	}
+
+	ts.removeFromStack(w)
+}
+
+//canvas above the taskbar
+func desktopArea() app.Rectangle {
+	return app.Rectangle{
+		gl.CanvasExtents.Y,
+		gl.CanvasExtents.X,
+		-gl.CanvasExtents.Y + app.TaskBarHeight,
+		-gl.CanvasExtents.X}
+}
+
+func tilesFor(layout string, area app.Rectangle, n int) []app.Rectangle {
+	if n == 0 {
+		return nil
+	}
+
+	switch layout {
+
+	case LayoutSplitH:
+		return splitEvenly(area, n, true)
+
+	case LayoutSplitV:
+		return splitEvenly(area, n, false)
+
+	case LayoutMaster:
+		if n == 1 {
+			return []app.Rectangle{area}
+		}
+
+		master := area
+		master.Right = area.Left + area.Width()*masterFraction
+		stack := area
+		stack.Left = master.Right
+		return append([]app.Rectangle{master}, splitEvenly(stack, n-1, false)...)
+
+	default: //grid
+		cols := int(math.Ceil(math.Sqrt(float64(n))))
+		rows := (n + cols - 1) / cols
+		tiles := []app.Rectangle{}
+
+		//(the last row may have fewer, which then get wider)
+		for i, row := range splitEvenly(area, rows, false) {
+			inRow := cols
+			if i == rows-1 {
+				inRow = n - cols*(rows-1)
+			}
+
+			tiles = append(tiles, splitEvenly(row, inRow, true)...)
+		}
+
+		return tiles
+
+	}
+}
+
+func splitEvenly(r app.Rectangle, n int, sideBySide bool) []app.Rectangle {
+	tiles := make([]app.Rectangle, n)
+
+	for i := range tiles {
+		tiles[i] = r
+
+		if sideBySide {
+			w := r.Width() / float32(n)
+			tiles[i].Left = r.Left + w*float32(i)
+			tiles[i].Right = tiles[i].Left + w
+		} else {
+			h := r.Height() / float32(n)
+			tiles[i].Top = r.Top - h*float32(i)
+			tiles[i].Bottom = tiles[i].Top - h
+		}
+	}
+
+	return tiles
+}
@@ -0,0 +1,78 @@
+package terminal
+
+import (
+	"github.com/skycoin/viscript/app"
+)
+
+//hides it (its tasks keep running), focusing whatever is topmost then.
+//its taskbar buttons stay, dimmed, to bring it back with
+func (ts *TerminalStack) Minimize(w *Window) {
+	w.Minimized = true
+
+	if w == ts.GetFocusedWindow() {
+		ts.focusTopmost()
+	}
+
+	ts.ApplyLayout()
+}
+
+//fills the canvas above the taskbar (grids of its panes growing to match)
+func (ts *TerminalStack) Maximize(w *Window) {
+	if !w.Maximized {
+		w.restoreBounds = *w.Bounds
+		w.Maximized = true
+	}
+
+	ts.SetFocused(w.Active().TerminalId)
+	ts.ApplyLayout()
+}
+
+//brings back a minimized window, or else puts a maximized one back where it was
+func (ts *TerminalStack) Restore(w *Window) {
+	if w.Minimized {
+		w.Minimized = false
+	} else if w.Maximized {
+		w.Maximized = false
+		*w.Bounds = w.restoreBounds
+		w.layoutPanes()
+	}
+
+	ts.SetFocused(w.Active().TerminalId)
+	ts.ApplyLayout()
+}
+
+func (ts *TerminalStack) ToggleMaximized(w *Window) {
+	if w.Maximized {
+		ts.Restore(w)
+	} else {
+		ts.Maximize(w)
+	}
+}
+
+//
+//
+//private
+//
+//
+
+//windows shown (rather than minimized) out of those given
+func shown(windows []*Window) []*Window {
+	s := []*Window{}
+
+	for _, w := range windows {
+		if !w.Minimized {
+			s = append(s, w)
+		}
+	}
+
+	return s
+}
+
+//the window's frame, whichever state it's in, for restoring to
+func (w *Window) normalBounds() app.Rectangle {
+	if w.Maximized {
+		return w.restoreBounds
+	}
+
+	return *w.Bounds
+}
@@ -1,11 +1,5 @@
 package terminal
 
-import (
-	"github.com/skycoin/viscript/msg"
-	"github.com/skycoin/viscript/viewport/gl"
-	"math"
-)
-
 func (t *Terminal) SetCursor(x, y int) {
 	if t.posIsValidElsePrint(x, y) {
 		t.Cursor.X = x
@@ -62,46 +56,3 @@ func (t *Terminal) putCharacter(char uint32) {
 		t.MoveRight()
 	}
 }
-
-func (t *Terminal) move(m msg.MessageMoveTerminal) {
-	//println("Terminal.move() - given x,y:", float32(m.X), float32(m.Y))
-	w := t.Bounds.Width()
-	h := t.Bounds.Height()
-	minimumVisibleSpan := float32(math.Min(float64(w), float64(h)))
-	minimumVisibleSpan /= 10
-
-	//FIXME?  positioning in desktop space by the size of Terminal's characters
-	//seems a bit wonky.  they could be any size for any given terminal, in theory.
-	//but i'm assuming the given coords should be similar
-	//to 80x25, so that it's similar to text mode positioning, in such a grid.
-	t.Bounds.Left = -gl.CanvasExtents.X + float32(m.X)*t.CharSize.X
-	t.Bounds.Top = gl.CanvasExtents.Y - float32(m.Y)*t.CharSize.Y
-	t.pushDownByTabHeight(&t.Bounds.Top)
-
-	//make sure at least a corner of the Terminal remains visible in the desktop viewport
-	if t.Bounds.Left < -gl.CanvasExtents.X-w+minimumVisibleSpan {
-		t.Bounds.Left = -gl.CanvasExtents.X - w + minimumVisibleSpan
-	}
-
-	if t.Bounds.Left > gl.CanvasExtents.X-minimumVisibleSpan {
-		t.Bounds.Left = gl.CanvasExtents.X - minimumVisibleSpan
-	}
-
-	if t.Bounds.Top > gl.CanvasExtents.Y+h-minimumVisibleSpan {
-		t.Bounds.Top = gl.CanvasExtents.Y + h - minimumVisibleSpan
-		t.pushDownByTabHeight(&t.Bounds.Top)
-	}
-
-	if t.Bounds.Top < -gl.CanvasExtents.Y+minimumVisibleSpan {
-		t.Bounds.Top = -gl.CanvasExtents.Y + minimumVisibleSpan
-		t.pushDownByTabHeight(&t.Bounds.Top)
-	}
-
-	//set the bottom right corner, now that upper left has been clamped to valid space
-	t.Bounds.Right = t.Bounds.Left + w
-	t.Bounds.Bottom = t.Bounds.Top - h
-}
-
-func (t *Terminal) pushDownByTabHeight(f *float32) {
-	*f -= t.CharSize.Y + t.BorderSize
-}
@@ -23,13 +23,18 @@ func (t *Terminal) UnpackMessage(message []byte) []byte {
 	case msg.TypeMoveTerminal:
 		var m msg.MessageMoveTerminal
 		msg.MustDeserialize(message, &m)
-		t.move(m)
+		t.Window.move(m)
 
 	case msg.TypePutChar:
 		var m msg.MessagePutChar
 		msg.MustDeserialize(message, &m)
 		t.putCharacter(m.Char)
 
+	case msg.TypeSetTitle:
+		var m msg.MessageSetTitle
+		msg.MustDeserialize(message, &m)
+		t.setTitle(m.Title)
+
 	case msg.TypeSetCharAt:
 		var m msg.MessageSetCharAt
 		msg.MustDeserialize(message, &m)
@@ -73,22 +78,10 @@ func (t *Terminal) onKey(m msg.MessageKey) {
 }
 
 func (t *Terminal) onMouseScroll(m msg.MessageMouseScroll) {
-	if m.HoldingControl {
+	if m.HoldingControl && !Terms.IsTiled() {
 		//only using m.Y because
 		//m.X is sideways scrolling (which most mice can't do)
 		y := float32(m.Y)
-		changeFactor := float32(1 + app.Clamp(y, -1, 1)/10)
-		newWidth := t.Bounds.Width() * changeFactor
-		newHeight := t.Bounds.Height() * changeFactor
-
-		if newWidth < 0.2 ||
-			newHeight < 0.2 {
-			return
-		}
-
-		t.Bounds.Right = t.Bounds.Left + newWidth
-		t.Bounds.Bottom = t.Bounds.Top - newHeight
-		t.CharSize.X *= changeFactor
-		t.CharSize.Y *= changeFactor
+		t.Window.zoom(float32(1 + app.Clamp(y, -1, 1)/10))
 	}
 }
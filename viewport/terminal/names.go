@@ -0,0 +1,82 @@
+package terminal
+
+import (
+	"errors"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+//besides their random ids, terminals can be given names by users (to use
+//in place of ids, in commands like "focus"), & titles by their tasks
+const maxTitleLength = 32 //(beyond which it gets abbreviated in tabs & on the taskbar)
+
+//its name, or else its id
+func (t *Terminal) Label() string {
+	if t.Name != "" {
+		return t.Name
+	}
+
+	return strconv.Itoa(int(t.TerminalId))
+}
+
+func (ts *TerminalStack) Rename(t *Terminal, name string) error {
+	name = strings.TrimSpace(name)
+
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return errors.New("terminal names need to be a single word")
+	}
+
+	if unicode.IsDigit(rune(name[0])) {
+		return errors.New("terminal names can't start with a digit (which would look like an id)")
+	}
+
+	if other := ts.getTerminalByName(name); other != nil && other != t {
+		return errors.New("there's already a terminal named \"" + name + "\"")
+	}
+
+	t.Name = name
+	t.setTabAndTaskBarButtonText()
+	ts.SetTaskBarButtonBounds()
+	return nil
+}
+
+//
+//
+//private
+//
+//
+
+func (ts *TerminalStack) getTerminalByName(name string) *Terminal {
+	for _, t := range ts.TermMap {
+		if t.Name != "" && strings.EqualFold(t.Name, name) {
+			return t
+		}
+	}
+
+	return nil
+}
+
+func (t *Terminal) setTitle(title string) {
+	t.Title = strings.TrimSpace(title)
+	t.setTabAndTaskBarButtonText()
+	Terms.SetTaskBarButtonBounds()
+}
+
+//label & title, as shown in its tab & on the taskbar
+func (t *Terminal) labelAndTitle() string {
+	s := t.Label()
+
+	if t.Title != "" {
+		title := t.Title
+		runes := []rune(title) //(apps set them, so don't cut a char in half)
+
+		if len(runes) > maxTitleLength {
+			title = string(runes[:maxTitleLength-3]) + "..."
+		}
+
+		s += ": " + title
+	}
+
+	return s
+}
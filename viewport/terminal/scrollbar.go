@@ -0,0 +1,92 @@
+package terminal
+
+import (
+	"github.com/skycoin/viscript/app"
+)
+
+const scrollBarWidth = 0.03 //(space for it is always kept, so the grid doesn't change when it shows up)
+
+//how many rows of its task's log are backscrolled, out of how many can be
+func (t *Terminal) Backscroll() (amount, max int) {
+	if ta := t.task(); ta != nil {
+		return ta.State.Backscroll()
+	}
+
+	return 0, 0
+}
+
+func (t *Terminal) ScrollBackTo(amount int) {
+	if ta := t.task(); ta != nil {
+		ta.State.ScrollBackTo(amount)
+	}
+}
+
+//the strip along the right side of the pane (inside its border) which the thumb moves in.
+//nil when there's nothing to scroll, so no bar is shown
+func (t *Terminal) ScrollBarBounds() *app.Rectangle {
+	if _, max := t.Backscroll(); max <= 0 {
+		return nil
+	}
+
+	return &app.Rectangle{
+		t.Bounds.Top - t.BorderSize,
+		t.Bounds.Right - t.BorderSize,
+		t.Bounds.Bottom + t.BorderSize,
+		t.Bounds.Right - t.BorderSize - scrollBarWidth}
+}
+
+//sized by how much of the log fits on a page, & at the bottom when not backscrolled
+func (t *Terminal) ScrollThumbBounds() *app.Rectangle {
+	track := t.ScrollBarBounds()
+	if track == nil {
+		return nil
+	}
+
+	amount, max := t.Backscroll()
+	page := t.task().State.NumBackscrollRows()
+
+	h := track.Height() * float32(page) / float32(page+max)
+	if h < scrollBarWidth {
+		h = scrollBarWidth
+	}
+
+	bottom := track.Bottom + (track.Height()-h)*float32(amount)/float32(max)
+	return &app.Rectangle{bottom + h, track.Right, bottom, track.Left}
+}
+
+//a page toward the point (for clicks beside the thumb)
+func (t *Terminal) PageScrollBarToward(p app.Vec2F) {
+	thumb := t.ScrollThumbBounds()
+	if thumb == nil {
+		return
+	}
+
+	amount, _ := t.Backscroll()
+	page := t.task().State.NumBackscrollRows()
+
+	if p.Y > thumb.Top {
+		t.ScrollBackTo(amount + page)
+	} else if p.Y < thumb.Bottom {
+		t.ScrollBackTo(amount - page)
+	}
+}
+
+//moves the thumb by dy (in GL space) from where it was when the log was scrolled back by "from"
+func (t *Terminal) DragScrollBar(from int, dy float32) {
+	track := t.ScrollBarBounds()
+	thumb := t.ScrollThumbBounds()
+	if track == nil || track.Height() <= thumb.Height() {
+		return
+	}
+
+	_, max := t.Backscroll()
+	rows := dy / (track.Height() - thumb.Height()) * float32(max)
+
+	if rows < 0 {
+		rows -= 0.5
+	} else {
+		rows += 0.5
+	}
+
+	t.ScrollBackTo(from + int(rows))
+}
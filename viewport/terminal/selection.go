@@ -0,0 +1,158 @@
+package terminal
+
+import (
+	"strings"
+
+	"github.com/skycoin/viscript/app"
+)
+
+//what both ends of a selection are widened to
+const (
+	SelectChars = iota
+	SelectWords //(double click)
+	SelectLines //(triple click)
+)
+
+//text selected by mouse, in character grid positions.  it's of what's
+//currently shown in the grid, so it works the same while backscrolled
+type Selection struct {
+	Active bool
+	Start  app.Vec2I //where it was started
+	End    app.Vec2I //where the pointer is now (either may come 1st)
+	By     int       //Select*
+}
+
+//the grid position under the given point (clamped to the grid)
+func (t *Terminal) GridPosAt(p app.Vec2F) app.Vec2I {
+	x := int((p.X - t.Bounds.Left - t.BorderSize) / t.CharSize.X)
+	y := int((t.Bounds.Top - t.BorderSize - p.Y) / t.CharSize.Y)
+
+	return app.Vec2I{clampInt(x, 0, t.GridSize.X-1), clampInt(y, 0, t.GridSize.Y-1)}
+}
+
+//inside the borders, where the chars are
+func (t *Terminal) TextAreaContains(p app.Vec2F) bool {
+	return p.X > t.Bounds.Left+t.BorderSize &&
+		p.X < t.Bounds.Right-t.BorderSize-scrollBarWidth &&
+		p.Y < t.Bounds.Top-t.BorderSize &&
+		p.Y > t.Bounds.Bottom+t.BorderSize
+}
+
+//a plain click only places the start, until the pointer is dragged
+//to another char.  words & lines are selected right away
+func (t *Terminal) StartSelection(p app.Vec2F, by int) {
+	pos := t.GridPosAt(p)
+	t.Selection = Selection{by != SelectChars, pos, pos, by}
+}
+
+func (t *Terminal) ExtendSelection(p app.Vec2F) {
+	t.Selection.End = t.GridPosAt(p)
+
+	if t.Selection.End != t.Selection.Start {
+		t.Selection.Active = true
+	}
+}
+
+func (t *Terminal) ClearSelection() {
+	t.Selection = Selection{}
+}
+
+func (t *Terminal) IsSelected(x, y int) bool {
+	if !t.Selection.Active {
+		return false
+	}
+
+	from, to := t.selectionRange()
+	return (y > from.Y || y == from.Y && x >= from.X) &&
+		(y < to.Y || y == to.Y && x <= to.X)
+}
+
+//each row of it with trailing blanks trimmed, joined by newlines
+func (t *Terminal) SelectedText() string {
+	if !t.Selection.Active {
+		return ""
+	}
+
+	from, to := t.selectionRange()
+	rows := []string{}
+
+	for y := from.Y; y <= to.Y; y++ {
+		row := ""
+
+		for x := 0; x < t.GridSize.X; x++ {
+			if !t.IsSelected(x, y) {
+				continue
+			}
+
+			if t.Chars[y][x] == 0 {
+				row += " "
+			} else {
+				row += string(rune(t.Chars[y][x]))
+			}
+		}
+
+		rows = append(rows, strings.TrimRight(row, " "))
+	}
+
+	return strings.Join(rows, "\n")
+}
+
+//
+//
+//private
+//
+//
+
+//in reading order, & widened by Selection.By
+func (t *Terminal) selectionRange() (from, to app.Vec2I) {
+	from, to = t.Selection.Start, t.Selection.End
+
+	if to.Y < from.Y || to.Y == from.Y && to.X < from.X {
+		from, to = to, from
+	}
+
+	switch t.Selection.By {
+
+	case SelectWords:
+		for from.X > 0 && isWordChar(t.Chars[from.Y][from.X-1]) {
+			from.X--
+		}
+
+		for to.X < t.GridSize.X-1 && isWordChar(t.Chars[to.Y][to.X+1]) {
+			to.X++
+		}
+
+	case SelectLines:
+		from.X = 0
+		to.X = t.GridSize.X - 1
+
+	}
+
+	return from, to
+}
+
+//follows the text when it all shifts up a line
+func (t *Terminal) scrollSelectionUp() {
+	t.Selection.Start.Y--
+	t.Selection.End.Y--
+
+	if t.Selection.Start.Y < 0 || t.Selection.End.Y < 0 {
+		t.ClearSelection()
+	}
+}
+
+func isWordChar(c uint32) bool {
+	return c != 0 && !strings.ContainsRune(" \t()[]{}<>\"'`,;|", rune(c))
+}
+
+func clampInt(n, min, max int) int {
+	if n < min {
+		return min
+	}
+
+	if n > max {
+		return max
+	}
+
+	return n
+}
@@ -0,0 +1,133 @@
+package terminal
+
+import (
+	"github.com/skycoin/viscript/app"
+)
+
+//windows being moved stick to the canvas edges & to each other, when near enough.
+//dropping one with the pointer at the edge of the canvas docks it to half of
+//the desktop (or a quarter, in a corner), like a desktop window manager would
+const (
+	snapThresh = 0.05 //how near edges get, before they snap together
+	dockThresh = 0.02 //how near the pointer gets to the edge of the canvas, to dock
+)
+
+//ends a move of the focused window, docking it if the pointer is at an edge
+func (ts *TerminalStack) DropFocusedTerminal(pointer app.Vec2F) {
+	w := ts.GetFocusedWindow()
+	if w == nil {
+		return
+	}
+
+	w.snapOffset = app.Vec2F{0, 0}
+
+	if tile, ok := dockTile(pointer); ok {
+		w.fitInto(tile)
+	}
+}
+
+//
+//
+//private
+//
+//
+
+//lines the window up with whatever edges are near (undoing the last snap 1st)
+func (ts *TerminalStack) snap(w *Window) {
+	w.MoveBy(app.Vec2F{-w.snapOffset.X, -w.snapOffset.Y})
+	r := w.outerBounds()
+	dx, dy := float32(snapThresh), float32(snapThresh)
+
+	//(d ends up as the shortest move onto a target, when it's below the threshold)
+	nearest := func(d *float32, from float32, targets ...float32) {
+		for _, to := range targets {
+			if abs(to-from) < abs(*d) {
+				*d = to - from
+			}
+		}
+	}
+
+	area := desktopArea()
+	nearest(&dx, r.Left, area.Left)
+	nearest(&dx, r.Right, area.Right)
+	nearest(&dy, r.Top, area.Top)
+	nearest(&dy, r.Bottom, area.Bottom)
+
+	for _, ow := range ts.Stacked() {
+		if ow == w {
+			continue
+		}
+
+		o := ow.outerBounds()
+
+		//(only edges that would meet)
+		if r.Top >= o.Bottom && r.Bottom <= o.Top {
+			nearest(&dx, r.Left, o.Left, o.Right)
+			nearest(&dx, r.Right, o.Left, o.Right)
+		}
+
+		if r.Left <= o.Right && r.Right >= o.Left {
+			nearest(&dy, r.Top, o.Top, o.Bottom)
+			nearest(&dy, r.Bottom, o.Top, o.Bottom)
+		}
+	}
+
+	w.snapOffset = app.Vec2F{0, 0}
+
+	if abs(dx) < snapThresh {
+		w.snapOffset.X = dx
+	}
+
+	if abs(dy) < snapThresh {
+		w.snapOffset.Y = dy
+	}
+
+	w.MoveBy(w.snapOffset)
+}
+
+//frame & id tabs
+func (w *Window) outerBounds() app.Rectangle {
+	r := *w.Bounds
+	r.Top += w.tabHeight()
+	return r
+}
+
+//half of the desktop by the edge the pointer is at, or a quarter in a corner
+func dockTile(pointer app.Vec2F) (app.Rectangle, bool) {
+	area := desktopArea()
+	tile := area
+	left := pointer.X <= area.Left+dockThresh
+	right := pointer.X >= area.Right-dockThresh
+	top := pointer.Y >= area.Top-dockThresh
+	bottom := pointer.Y <= area.Bottom+dockThresh //(just above the taskbar)
+
+	if !left && !right && !top && !bottom {
+		return tile, false
+	}
+
+	if left {
+		tile.Right = area.Left + area.Width()/2
+	}
+
+	if right {
+		tile.Left = area.Left + area.Width()/2
+	}
+
+	if top {
+		tile.Bottom = area.Top - area.Height()/2
+	}
+
+	if bottom {
+		tile.Top = area.Top - area.Height()/2
+	}
+
+	return tile, true
+}
+
+func abs(f float32) float32 {
+	if f < 0 {
+		return -f
+	}
+
+	return f
+}
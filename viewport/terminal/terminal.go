@@ -4,8 +4,8 @@ import (
 	"github.com/skycoin/viscript/app"
 	"github.com/skycoin/viscript/config"
 	"github.com/skycoin/viscript/hypervisor"
+	termTask "github.com/skycoin/viscript/hypervisor/task/terminal"
 	"github.com/skycoin/viscript/msg"
-	"strconv"
 )
 
 const (
@@ -13,16 +13,19 @@ const (
 	MinimumColumns = 16 //don't allow resizing smaller than this
 
 	//private
-	path = "viewport/terminal/terminal"
+	path       = "viewport/terminal/terminal"
+	borderSize = 0.013
 )
 
 var numOOB int //number of out of bound characters
 
 type Terminal struct {
 	TerminalId        msg.TerminalId
+	Name              string //given by the user, & usable in place of the id (see names.go)
+	Title             string //given by its task, or an app attached to it
 	TabText           string
 	TaskBarButtonText string
-	FixedSize         bool
+	Window            *Window //which this is a pane of
 	AttachedTask      msg.TaskId
 	OutChannelId      uint32 //id of pubsub channel
 	InChannel         chan []byte
@@ -32,14 +35,13 @@ type Terminal struct {
 	Cursor      app.Vec2I //user controlled position (within command prompt row/s)
 	GridSize    app.Vec2I //number of characters across
 	Chars       [][]uint32
+	Selection   Selection //by mouse (see selection.go)
 
 	//float/GL space
 	//(mouse pos events & frame buffer sizes are the only things that use pixels)
-	BorderSize    float32
-	CharSize      app.Vec2F
-	Bounds        *app.Rectangle
-	TaskBarButton *app.Rectangle //(...bounds)
-	Depth         float32        //0 for lowest/furthest
+	BorderSize float32
+	CharSize   app.Vec2F
+	Bounds     *app.Rectangle //of this pane (the whole window, unless it's split)
 }
 
 func (t *Terminal) Init() {
@@ -47,11 +49,11 @@ func (t *Terminal) Init() {
 
 	t.TerminalId = msg.RandTerminalId()
 	t.InChannel = make(chan []byte, msg.ChannelCapacity)
-	t.BorderSize = 0.013
+	t.BorderSize = borderSize
 	t.GridSize = app.Vec2I{80, 32}
 	t.setTabAndTaskBarButtonText()
 	t.setupNewGrid()
-	t.CharSize.X = (t.Bounds.Width() - t.BorderSize*2) / float32(t.GridSize.X)
+	t.CharSize.X = (t.Bounds.Width() - t.BorderSize*2 - scrollBarWidth) / float32(t.GridSize.X)
 	t.CharSize.Y = (t.Bounds.Height() - t.BorderSize*2) / float32(t.GridSize.Y)
 
 	//set grid's initial data
@@ -60,11 +62,6 @@ func (t *Terminal) Init() {
 	t.SetCursor(1, 1)
 	t.CurrFlowPos.X = 1
 	t.CurrFlowPos.Y = 1
-
-	//push down window (in y dimension) by size of id tab
-	tabOffset := t.BorderSize + t.CharSize.Y
-	t.Bounds.Top -= tabOffset
-	t.Bounds.Bottom -= tabOffset
 }
 
 func (t *Terminal) Tick() {
@@ -73,74 +70,6 @@ func (t *Terminal) Tick() {
 	}
 }
 
-func (t *Terminal) GetTabBounds() *app.Rectangle {
-	return &app.Rectangle{
-		t.Bounds.Top + t.CharSize.Y + t.BorderSize,
-		t.Bounds.Left + t.BorderSize*2 + t.CharSize.X*float32(len(t.TabText)),
-		t.Bounds.Top - t.BorderSize, //letting it overlap bounds for simpler drawing
-		t.Bounds.Left}
-}
-
-func (t *Terminal) ResizeHorizontally(newRight float32) {
-	delta := newRight - t.Bounds.Right
-	sx := t.GridSize.X
-
-	// if keyboard.AltKeyIsDown {
-	// 	//if we re-enable holding CTRL for pixel resizing, will need to adjust GridSize too
-	// 	t.Bounds.Right = newRight
-	// } else {
-	for delta > t.CharSize.X {
-		delta -= t.CharSize.X
-
-		t.Bounds.Right += t.CharSize.X
-		t.GridSize.X++
-	}
-
-	for delta < -t.CharSize.X {
-		delta += t.CharSize.X
-
-		if t.GridSize.X > MinimumColumns {
-			t.Bounds.Right -= t.CharSize.X
-			t.GridSize.X--
-		}
-	}
-	// }
-
-	if /* x changed */ sx != t.GridSize.X {
-		t.setupNewGrid()
-	}
-}
-
-func (t *Terminal) ResizeVertically(newBottom float32) {
-	delta := newBottom - t.Bounds.Bottom
-	sy := t.GridSize.Y
-
-	// if keyboard.AltKeyIsDown {
-	// 	//if we re-enable holding CTRL for pixel resizing, will need to adjust GridSize too
-	// 	t.Bounds.Bottom = newBottom
-	// } else {
-	for delta > t.CharSize.Y {
-		delta -= t.CharSize.Y
-
-		if t.GridSize.Y > NumPromptLines+2 {
-			t.Bounds.Bottom += t.CharSize.Y
-			t.GridSize.Y--
-		}
-	}
-
-	for delta < -t.CharSize.Y {
-		delta += t.CharSize.Y
-
-		t.Bounds.Bottom -= t.CharSize.Y
-		t.GridSize.Y++
-	}
-	// }
-
-	if /* y changed */ sy != t.GridSize.Y {
-		t.setupNewGrid()
-	}
-}
-
 func (t *Terminal) RelayToTask(message []byte) {
 	hypervisor.DbusGlobal.PublishTo(t.OutChannelId, message)
 }
@@ -167,6 +96,8 @@ func (t *Terminal) NewLine() {
 				t.Chars[y][x] = t.Chars[y+1][x]
 			}
 		}
+
+		t.scrollSelectionUp()
 	}
 
 	if config.Global.Settings.RunHeadless {
@@ -183,13 +114,40 @@ func (t *Terminal) GetVisualInfo() msg.MessageVisualInfo {
 		uint32(NumPromptLines)}
 }
 
+func (t *Terminal) HasExternalAppAttached() bool {
+	ta := t.task()
+	return ta != nil && ta.HasExternalAppAttached()
+}
+
+//leaves it running in the background (see "list_apps")
+func (t *Terminal) DetachExternalApp() {
+	if t.HasExternalAppAttached() {
+		t.task().DetachExternalApp()
+	}
+}
+
+//stops it (gracefully, if it lets us)
+func (t *Terminal) KillExternalApp() {
+	if t.HasExternalAppAttached() {
+		t.task().ExitExternalApp()
+	}
+}
+
 //
 //
 //private
 //
 //
 
+//(nil once it's gone)
+func (t *Terminal) task() *termTask.Task {
+	ta, _ := hypervisor.GlobalTasks.TaskMap[t.AttachedTask].(*termTask.Task)
+	return ta
+}
+
 func (t *Terminal) clear() {
+	t.ClearSelection()
+
 	for y := 0; y < t.GridSize.Y; y++ {
 		for x := 0; x < t.GridSize.X; x++ {
 			t.Chars[y][x] = 0
@@ -235,20 +193,21 @@ func (t *Terminal) posIsValidElsePrint(X, Y int) bool { //...any errors to OS bo
 }
 
 func (t *Terminal) setTabAndTaskBarButtonText() {
-	s := strconv.Itoa(int(t.TerminalId))
+	s := t.labelAndTitle()
 	t.TaskBarButtonText = s
 
-	if t.FixedSize {
+	if t.Window.FixedSize {
 		s += " (FixedSize)"
 	}
 
-	s += "  " //for tab size calculations (allowing space for close button)
+	s += " " //(a gap before the buttons, which GetTabBounds() allows for)
 	t.TabText = s
 }
 
 func (t *Terminal) setupNewGrid() {
 	t.CurrFlowPos = app.Vec2I{0, 0}
 	t.Chars = [][]uint32{}
+	t.ClearSelection()
 
 	//allocate every grid position in the "Chars" multi-dimensional slice
 	for y := 0; y < t.GridSize.Y; y++ {
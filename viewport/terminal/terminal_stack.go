@@ -1,7 +1,9 @@
 package terminal
 
 import (
+	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/skycoin/viscript/app"
 	"github.com/skycoin/viscript/hypervisor"
@@ -16,13 +18,22 @@ var Terms = TerminalStack{}
 
 type TerminalStack struct {
 	FocusedId msg.TerminalId
-	TermMap   map[msg.TerminalId]*Terminal
+	TermMap   map[msg.TerminalId]*Terminal //every pane of every window
+	Windows   []*Window                    //of every workspace, in the order they were added
+	Layout    string                       //one of Layouts (see layout.go)
+
+	Workspaces      []*Workspace //(see workspace.go)
+	ActiveWorkspace *Workspace
+
+	TrayWidth float32 //kept free at the right end of the taskbar (see viewport/tray.go)
+
+	//private
+	zOrder []*Window //of every workspace, bottom to top (see zorder.go)
 
 	//private (next/new terminal spawn vars)
 	w          float32 //default width
 	h          float32 //default height
 	nextRect   app.Rectangle
-	nextDepth  float32
 	nextOffset app.Vec2F //how far from previous terminal
 }
 
@@ -42,7 +53,9 @@ func (ts *TerminalStack) Init() {
 		top - ts.h,
 		left}
 
-	//initial terminal window
+	//initial workspace & terminal window
+	ts.ActiveWorkspace = &Workspace{Name: DefaultWorkspaceName, TaskBarButton: &app.Rectangle{}}
+	ts.Workspaces = []*Workspace{ts.ActiveWorkspace}
 	Terms.Add()
 }
 
@@ -54,21 +67,25 @@ func (ts *TerminalStack) Add() msg.TerminalId {
 func (ts *TerminalStack) AddWithFixedSizeState(fixedSize bool) msg.TerminalId { //^^^
 	println("<TerminalStack>.AddWithFixedSizeState()")
 
-	ts.nextDepth += ts.nextOffset.X / 10 // done first, cuz desktop is at 0
-
-	tid := msg.RandTerminalId() //terminal id
-	ts.TermMap[tid] = &Terminal{
-		Depth: ts.nextDepth,
+	w := &Window{
+		Workspace: ts.ActiveWorkspace,
+		FixedSize: fixedSize,
 		Bounds: &app.Rectangle{
 			ts.nextRect.Top,
 			ts.nextRect.Right,
 			ts.nextRect.Bottom,
 			ts.nextRect.Left},
 		TaskBarButton: &app.Rectangle{}}
-	ts.TermMap[tid].FixedSize = fixedSize
-	ts.TermMap[tid].Init()
-	println("AddWithFixed...... - ts.TermMap[tid].TerminalId:", ts.TermMap[tid].TerminalId)
-	ts.SetFocused(ts.TermMap[tid].TerminalId)
+
+	tid, t := ts.addPane(w, *w.Bounds)
+	println("AddWithFixed...... - t.TerminalId:", t.TerminalId)
+	w.Tabs = []*Tab{newTab(t)}
+	ts.Windows = append(ts.Windows, w)
+
+	//the 1st pane sets the size of chars, & the window goes below its id tab
+	w.charSize = t.CharSize
+	w.MoveBy(app.Vec2F{0, -w.tabHeight()})
+	ts.SetFocused(t.TerminalId)
 
 	//set next Terminal rectangle
 	ts.nextRect.Top -= ts.nextOffset.Y
@@ -88,15 +105,154 @@ func (ts *TerminalStack) AddWithFixedSizeState(fixedSize bool) msg.TerminalId {
 	}
 
 	//finalize
-	ts.SetupTerminal(tid)
+	ts.ApplyLayout()
 	ts.SetTaskBarButtonBounds()
 	return tid
 }
 
+//adds a tab (running its own task) to the window, & shows it
+func (ts *TerminalStack) AddTab(w *Window) msg.TerminalId {
+	println("<TerminalStack>.AddTab()")
+
+	tid, t := ts.addPane(w, *w.Bounds)
+	w.Tabs = append(w.Tabs, newTab(t))
+	w.layoutPanes() //(its grid gets the size of the others)
+	ts.SetFocused(t.TerminalId)
+	ts.SetTaskBarButtonBounds()
+	return tid
+}
+
+//moves tab i into a window of its own, which gets grabbed by the
+//middle of its tab at 'at' (so it can go on being dragged)
+func (ts *TerminalStack) DetachTab(w *Window, i int, at app.Vec2F) *Window {
+	if len(w.Tabs) < 2 {
+		return w
+	}
+
+	tab := w.Tabs[i]
+	w.removeTab(i)
+
+	nw := &Window{
+		Workspace:     w.Workspace,
+		FixedSize:     w.FixedSize,
+		AlwaysOnTop:   w.AlwaysOnTop,
+		Bounds:        &app.Rectangle{},
+		TaskBarButton: &app.Rectangle{},
+		Tabs:          []*Tab{tab},
+		charSize:      w.charSize}
+	*nw.Bounds = w.normalBounds()
+
+	for _, t := range nw.AllTerms() {
+		t.Window = nw
+	}
+
+	nw.layoutPanes() //(in case w was maximized)
+
+	tb := nw.GetTabBounds(0)
+	nw.MoveBy(app.Vec2F{at.X - tb.CenterX(), at.Y - tb.CenterY()})
+	ts.Windows = append(ts.Windows, nw)
+	ts.SetFocused(tab.Active().TerminalId)
+	ts.ApplyLayout()
+	ts.SetTaskBarButtonBounds()
+	return nw
+}
+
 func (ts *TerminalStack) Defocus() {
 	ts.FocusedId = 0
 }
 
+//focuses the window 'step' places after the focused one (of those shown in the
+//active workspace), in the order they were added (or the first one, when none is focused)
+func (ts *TerminalStack) FocusNext(step int) {
+	windows := shown(ts.Visible())
+	if len(windows) == 0 {
+		return
+	}
+
+	next := 0
+	foc := ts.GetFocusedWindow()
+
+	for i, w := range windows {
+		if w == foc {
+			next = (i + step + len(windows)) % len(windows)
+		}
+	}
+
+	ts.SetFocused(windows[next].Active().TerminalId)
+}
+
+//shows the tab 'step' places along, within the focused window
+func (ts *TerminalStack) FocusTab(step int) {
+	w := ts.GetFocusedWindow()
+	if w == nil {
+		return
+	}
+
+	next := (w.ActiveTab + step + len(w.Tabs)) % len(w.Tabs)
+	ts.SetFocused(w.Tabs[next].Active().TerminalId)
+}
+
+//moves keyboard focus 'step' panes along, within the focused window
+func (ts *TerminalStack) FocusPane(step int) {
+	w := ts.GetFocusedWindow()
+	if w == nil {
+		return
+	}
+
+	terms := w.Terms()
+
+	for i, t := range terms {
+		if t.TerminalId == ts.FocusedId {
+			ts.SetFocused(terms[(i+step+len(terms))%len(terms)].TerminalId)
+			return
+		}
+	}
+}
+
+//splits a pane in 2, the new half (which gets focus) running its own task
+func (ts *TerminalStack) Split(id msg.TerminalId, sideBySide bool) (msg.TerminalId, error) {
+	t := ts.GetTerminal(id)
+	if t == nil {
+		return 0, errors.New("no terminal has the id " + strconv.Itoa(int(id)))
+	}
+
+	w := t.Window
+	halves := splitEvenly(*t.Bounds, 2, sideBySide)
+
+	if halves[0].Width() < w.charSize.X*MinimumColumns+borderSize*2+scrollBarWidth ||
+		halves[0].Height() < w.charSize.Y*(NumPromptLines+2)+borderSize*2 {
+		return 0, errors.New("that pane is too small to split")
+	}
+
+	_, nt := ts.addPane(w, halves[1])
+	leaf := w.Tabs[w.TabOf(id)].Panes.find(id)
+	leaf.Halves = [2]*Pane{{Term: t}, {Term: nt}}
+	leaf.Term = nil
+	leaf.SideBySide = sideBySide
+	w.layoutPanes()
+
+	ts.SetFocused(nt.TerminalId)
+	return nt.TerminalId, nil
+}
+
+func (ts *TerminalStack) GetFocusedWindow() *Window {
+	if t := ts.GetFocusedTerminal(); t != nil {
+		return t.Window
+	}
+
+	return nil
+}
+
+func (ts *TerminalStack) GetTerminal(id msg.TerminalId) *Terminal {
+	for _, t := range ts.TermMap {
+		if t.TerminalId == id {
+			return t
+		}
+	}
+
+	return nil
+}
+
 func (ts *TerminalStack) GetFocusedTerminal() *Terminal {
 	for key, t := range ts.TermMap {
 		if t.TerminalId == ts.FocusedId {
@@ -110,14 +266,14 @@ func (ts *TerminalStack) GetFocusedTerminal() *Terminal {
 func (ts *TerminalStack) MoveFocusedTerminal(hiResDelta app.Vec2F, mouseDeltaSinceClick *app.Vec2F) {
 	d := mouseDeltaSinceClick
 	println("MoveFocusedTerminal()   -   ts.FocusedId:", ts.FocusedId)
-	foc := ts.GetFocusedTerminal()
+	foc := ts.GetFocusedWindow()
 
 	if foc == nil {
 		return
 	}
 
-	cs := foc.CharSize
-	fb := foc.Bounds
+	cs := foc.charSize
+	fb := foc //(moves its panes along)
 
 	if keyboard.AltKeyIsDown { //smooth, high resolution
 		fb.MoveBy(hiResDelta)
@@ -138,6 +294,8 @@ func (ts *TerminalStack) MoveFocusedTerminal(hiResDelta app.Vec2F, mouseDeltaSin
 			fb.MoveBy(app.Vec2F{0, -cs.Y})
 		}
 	}
+
+	ts.snap(foc) //(see snap.go)
 }
 
 func (ts *TerminalStack) Remove(id msg.TerminalId) {
@@ -150,13 +308,11 @@ func (ts *TerminalStack) Remove(id msg.TerminalId) {
 
 		if id == term.TerminalId {
 			trash = key
-
-			if term == ts.TermMap[ts.FocusedId] {
-				ts.FocusedId = 0
-			}
 		}
 	}
 
+	w := ts.TermMap[trash].Window
+
 	attTask := ts.TermMap[trash].AttachedTask
 	outId := hypervisor.GlobalTasks.TaskMap[attTask].GetOutputChannelId()
 
@@ -174,9 +330,54 @@ func (ts *TerminalStack) Remove(id msg.TerminalId) {
 	delete(ts.TermMap, trash)
 	//println("len of TermMap:", len(ts.TermMap))
 
+	//take it out of its tab, the tab out of its window,
+	//& the window off the desktop (when each was the last)
+	i := w.TabOf(id)
+	tab := w.Tabs[i]
+
+	if tab.Panes.Term != nil {
+		w.removeTab(i)
+	} else {
+		tab.Panes.remove(id)
+		w.layoutPanes()
+
+		if tab.ActiveId == id {
+			tab.ActiveId = tab.Active().TerminalId //(the 1st left)
+		}
+	}
+
+	if len(w.Tabs) == 0 {
+		ts.removeWindow(w)
+	}
+
+	if ts.FocusedId == id {
+		ts.FocusedId = 0
+
+		if len(w.Tabs) > 0 {
+			ts.SetFocused(w.Active().TerminalId)
+		} else {
+			ts.focusTopmost() //(if any are left)
+		}
+	}
+
+	ts.ApplyLayout()
 	ts.SetTaskBarButtonBounds()
 }
 
+//closes every pane of every tab of a window
+func (ts *TerminalStack) RemoveWindow(w *Window) {
+	for _, t := range w.AllTerms() {
+		ts.Remove(t.TerminalId)
+	}
+}
+
+//closes every pane of tab i
+func (ts *TerminalStack) RemoveTab(w *Window, i int) {
+	for _, t := range w.Tabs[i].Terms() {
+		ts.Remove(t.TerminalId)
+	}
+}
+
 func (ts *TerminalStack) SetupTerminal(termId msg.TerminalId) {
 	//make it's task
 	task := termTask.MakeNewTask()
@@ -219,54 +420,29 @@ func (ts *TerminalStack) SetupTerminal(termId msg.TerminalId) {
 }
 
 func (ts *TerminalStack) SetFocused(topmostId msg.TerminalId) {
-	newZ := float32(9.9) //FIXME (@ all places of this var) IF you ever want more than (about) 50 terms
 	ts.FocusedId = topmostId
 
-	//cycle through terminals
-	noneFocused := true
-	for key, term := range ts.TermMap {
-		if term.TerminalId == ts.FocusedId {
-			noneFocused = false
-			ts.TermMap[key].Depth = newZ
-		}
-	}
-
-	if noneFocused {
-		ts.TermMap[topmostId].Depth = newZ
+	top := ts.GetTerminal(topmostId)
+	if top == nil {
+		return
 	}
 
-	//make list of REST of the terms (excluding currently focused term)
-	theRest := []*Terminal{}
-
-	for key, t := range ts.TermMap {
-		if t.TerminalId != ts.FocusedId {
-			theRest = append(theRest, ts.TermMap[key])
-		}
+	if top.Window.Workspace != ts.ActiveWorkspace {
+		ts.showWorkspace(top.Window.Workspace)
 	}
 
-	//sort them (top/closest at the start of list)
-	fullySorted := false
-	for !fullySorted {
-		fullySorted = true
-
-		for i := 0; i < len(theRest)-1; i++ {
-			if theRest[i].Depth < theRest[i+1].Depth {
-				theNext := theRest[i+1]
-				theRest[i+1] = theRest[i]
-				theRest[i] = theNext
-				fullySorted = false
-			}
-		}
+	if top.Window.Minimized { //(brought back)
+		top.Window.Minimized = false
+		ts.ApplyLayout()
 	}
 
-	//assign receding z/depth values
-	for _, t := range theRest {
-		newZ -= 0.2
-		t.Depth = newZ
-	}
+	top.Window.ActiveTab = top.Window.TabOf(top.TerminalId)
+	top.Window.Tab().ActiveId = top.TerminalId
+	ts.Raise(top.Window)
 }
 
 func (ts *TerminalStack) Tick() {
+	//(every workspace, or tasks of hidden terminals would block, on full channels)
 	for _, term := range ts.TermMap {
 		term.Tick()
 	}
@@ -280,20 +456,73 @@ func (ts *TerminalStack) SetTaskBarButtonBounds() {
 	//leftover width for taskbar buttons
 	lWid := gl.CanvasExtents.X - x
 	lWid -= app.TaskBarBorderSpan //for right edge of taskbar border
-	maxWid /* per Terminal button */ := lWid / float32(len(ts.TermMap))
+	lWid -= ts.TrayWidth
+	numTabs := 0
+	windows := ts.Visible()
+
+	//workspace switcher 1st
+	for _, ws := range ts.Workspaces {
+		ws.TaskBarButton.Bottom = -gl.CanvasExtents.Y + app.TaskBarBorderSpan
+		ws.TaskBarButton.Top = -gl.CanvasExtents.Y - app.TaskBarBorderSpan + app.TaskBarHeight
+		ws.TaskBarButton.Left = x
+		x += app.TaskBarCharWid*float32(len(ws.Name)) + app.TaskBarBorderSpan*2
+		ws.TaskBarButton.Right = x
+		lWid -= ws.TaskBarButton.Width()
+	}
 
-	for _, term := range ts.TermMap {
-		currButtonWid := app.TaskBarCharWid*float32(len(term.TaskBarButtonText)) + app.TaskBarBorderSpan*2
-		term.TaskBarButton.Bottom = -gl.CanvasExtents.Y + app.TaskBarBorderSpan
-		term.TaskBarButton.Top = -gl.CanvasExtents.Y - app.TaskBarBorderSpan + app.TaskBarHeight
-		term.TaskBarButton.Left = x
+	for _, w := range windows {
+		numTabs += len(w.Tabs)
+	}
 
-		if currButtonWid > maxWid {
-			x += maxWid
-		} else {
-			x += currButtonWid
+	//tabs are grouped by window, with gaps between the groups (& the switcher)
+	gap := app.TaskBarBorderSpan * 2
+	lWid -= gap * float32(len(windows)+1)
+	maxWid /* per tab button */ := lWid / float32(numTabs)
+	x += gap
+
+	for _, w := range windows {
+		w.TaskBarButton.Bottom = -gl.CanvasExtents.Y + app.TaskBarBorderSpan
+		w.TaskBarButton.Top = -gl.CanvasExtents.Y - app.TaskBarBorderSpan + app.TaskBarHeight
+		w.TaskBarButton.Left = x
+
+		for _, tab := range w.Tabs {
+			currButtonWid := app.TaskBarCharWid*float32(len(tab.Active().TaskBarButtonText)) + app.TaskBarBorderSpan*2
+			*tab.TaskBarButton = *w.TaskBarButton
+			tab.TaskBarButton.Left = x
+
+			if currButtonWid > maxWid {
+				x += maxWid
+			} else {
+				x += currButtonWid
+			}
+
+			tab.TaskBarButton.Right = x
 		}
 
-		term.TaskBarButton.Right = x
+		w.TaskBarButton.Right = x
+		x += gap
 	}
 }
+
+//
+//
+//private
+//
+//
+
+func newTab(t *Terminal) *Tab {
+	return &Tab{
+		Panes:         &Pane{Term: t},
+		ActiveId:      t.TerminalId,
+		TaskBarButton: &app.Rectangle{}}
+}
+
+//makes a terminal (with its task) as a pane of w.  returns its TermMap key too
+func (ts *TerminalStack) addPane(w *Window, bounds app.Rectangle) (msg.TerminalId, *Terminal) {
+	tid := msg.RandTerminalId() //terminal id
+	t := &Terminal{Window: w, Bounds: &bounds}
+	ts.TermMap[tid] = t
+	t.Init()
+	ts.SetupTerminal(tid)
+	return tid, t
+}
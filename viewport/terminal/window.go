@@ -0,0 +1,379 @@
+package terminal
+
+import (
+	"math"
+
+	"github.com/skycoin/viscript/app"
+	"github.com/skycoin/viscript/msg"
+	"github.com/skycoin/viscript/viewport/gl"
+)
+
+//a window frame with 1 or more id tabs above it.  each tab is split into
+//1 or more panes, & each pane is a Terminal, with its own task, dbus channels & grid
+type Window struct {
+	Workspace     *Workspace
+	Bounds        *app.Rectangle //the frame (the tabs sit above it)
+	TaskBarButton *app.Rectangle //(...bounds) around the buttons of all its tabs
+	Depth         float32        //0 for lowest/furthest (follows the stack, see zorder.go)
+	AlwaysOnTop   bool
+	Minimized     bool //(hidden, see minmax.go)
+	Maximized     bool
+	FixedSize     bool
+	Tabs          []*Tab
+	ActiveTab     int //index into Tabs of the one shown
+
+	//private
+	charSize      app.Vec2F     //nominal, which panes aim for whenever they're refitted
+	restoreBounds app.Rectangle //from before it was maximized
+	snapOffset    app.Vec2F     //how far it got pulled to the edges near it, while being moved
+}
+
+//sides of a window (as flags), for resizing by
+const (
+	EdgeLeft = 1 << iota
+	EdgeRight
+	EdgeTop
+	EdgeBottom
+)
+
+//buttons at the right end of each id tab, going right to left
+const (
+	TabButtonClose = iota
+	TabButtonMaximize
+	TabButtonMinimize
+	numTabButtons
+)
+
+type Tab struct {
+	Panes         *Pane
+	ActiveId      msg.TerminalId //pane that has (or last had) keyboard focus
+	TaskBarButton *app.Rectangle //(...bounds)
+}
+
+//a node of a window's tree of splits.
+//leaves hold a terminal, the others 2 halves
+type Pane struct {
+	Term       *Terminal
+	SideBySide bool //(otherwise on top of each other)
+	Halves     [2]*Pane
+}
+
+//panes of the tab shown, from top/left to bottom/right
+func (w *Window) Terms() []*Terminal {
+	return w.Tab().Panes.terms(nil)
+}
+
+//panes of every tab
+func (w *Window) AllTerms() []*Terminal {
+	terms := []*Terminal{}
+
+	for _, tab := range w.Tabs {
+		terms = tab.Panes.terms(terms)
+	}
+
+	return terms
+}
+
+//the tab shown
+func (w *Window) Tab() *Tab {
+	return w.Tabs[w.ActiveTab]
+}
+
+//pane of the tab shown that has (or last had) keyboard focus
+func (w *Window) Active() *Terminal {
+	return w.Tab().Active()
+}
+
+//index of the tab holding terminal id (-1 when none does)
+func (w *Window) TabOf(id msg.TerminalId) int {
+	for i, tab := range w.Tabs {
+		if tab.Panes.find(id) != nil {
+			return i
+		}
+	}
+
+	return -1
+}
+
+//its panes, from top/left to bottom/right
+func (tab *Tab) Terms() []*Terminal {
+	return tab.Panes.terms(nil)
+}
+
+func (tab *Tab) Active() *Terminal {
+	terms := tab.Panes.terms(nil)
+
+	for _, t := range terms {
+		if t.TerminalId == tab.ActiveId {
+			return t
+		}
+	}
+
+	return terms[0]
+}
+
+//tabs go left to right, each as wide as its text & buttons
+func (w *Window) GetTabBounds(i int) *app.Rectangle {
+	r := &app.Rectangle{
+		w.Bounds.Top + w.charSize.Y + borderSize,
+		w.Bounds.Left,
+		w.Bounds.Top - borderSize, //letting it overlap bounds for simpler drawing
+		w.Bounds.Left}
+
+	for j := 0; j <= i; j++ {
+		r.Left = r.Right
+		r.Right += borderSize*2 + w.charSize.X*float32(len(w.Tabs[j].Active().TabText))
+		r.Right += numTabButtons * (w.charSize.X + borderSize*2)
+	}
+
+	return r
+}
+
+//around every tab
+func (w *Window) GetTabStripBounds() *app.Rectangle {
+	r := w.GetTabBounds(len(w.Tabs) - 1)
+	r.Left = w.Bounds.Left
+	return r
+}
+
+func (w *Window) GetCloseButtonBounds(i int) *app.Rectangle {
+	return w.GetTabButtonBounds(i, TabButtonClose)
+}
+
+//of 1 of the TabButton* of tab i
+func (w *Window) GetTabButtonBounds(i, button int) *app.Rectangle {
+	r := w.GetTabBounds(i)
+	r.Right -= float32(button) * (w.charSize.X + borderSize*2)
+	r.Left = r.Right - w.charSize.X - borderSize*2
+	r.Bottom = r.Top - w.charSize.Y - borderSize
+	return r
+}
+
+//moves the edges given (Edge* flags) toward 'to', by whole chars,
+//never leaving fewer than the minimum columns & rows
+func (w *Window) Resize(edges int, to app.Vec2F) {
+	cs := w.charSize
+	prev := *w.Bounds
+	b := w.Bounds
+	minWidth := cs.X*MinimumColumns + borderSize*2 + scrollBarWidth
+	minHeight := cs.Y*(NumPromptLines+2) + borderSize*2
+
+	if edges&EdgeLeft != 0 {
+		b.Left = stepToward(b.Left, to.X, cs.X)
+
+		for b.Width() < minWidth {
+			b.Left -= cs.X
+		}
+	}
+
+	if edges&EdgeRight != 0 {
+		b.Right = stepToward(b.Right, to.X, cs.X)
+
+		for b.Width() < minWidth {
+			b.Right += cs.X
+		}
+	}
+
+	if edges&EdgeTop != 0 {
+		b.Top = stepToward(b.Top, to.Y, cs.Y)
+
+		for b.Height() < minHeight {
+			b.Top += cs.Y
+		}
+	}
+
+	if edges&EdgeBottom != 0 {
+		b.Bottom = stepToward(b.Bottom, to.Y, cs.Y)
+
+		for b.Height() < minHeight {
+			b.Bottom -= cs.Y
+		}
+	}
+
+	if /* any edge changed */ *b != prev {
+		w.layoutPanes()
+	}
+}
+
+func (w *Window) MoveBy(delta app.Vec2F) {
+	w.Bounds.MoveBy(delta)
+
+	for _, t := range w.AllTerms() {
+		t.Bounds.MoveBy(delta)
+	}
+}
+
+//
+//
+//private
+//
+//
+
+func (w *Window) tabHeight() float32 {
+	return w.charSize.Y + borderSize
+}
+
+//refits every pane of every tab (& its grid) into its share of the frame
+func (w *Window) layoutPanes() {
+	for _, tab := range w.Tabs {
+		tab.Panes.fitInto(*w.Bounds, w.charSize)
+	}
+}
+
+func (w *Window) removeTab(i int) {
+	w.Tabs = append(w.Tabs[:i], w.Tabs[i+1:]...)
+
+	if w.ActiveTab > i || w.ActiveTab == len(w.Tabs) {
+		w.ActiveTab--
+	}
+}
+
+//covers the tile exactly, id tab included
+func (w *Window) fitInto(tile app.Rectangle) {
+	*w.Bounds = tile
+	w.Bounds.Top -= w.tabHeight()
+	w.layoutPanes()
+}
+
+func (w *Window) zoom(changeFactor float32) {
+	newWidth := w.Bounds.Width() * changeFactor
+	newHeight := w.Bounds.Height() * changeFactor
+
+	if newWidth < 0.2 ||
+		newHeight < 0.2 {
+		return
+	}
+
+	w.Bounds.Right = w.Bounds.Left + newWidth
+	w.Bounds.Bottom = w.Bounds.Top - newHeight
+	w.charSize.X *= changeFactor
+	w.charSize.Y *= changeFactor
+	w.layoutPanes()
+}
+
+func (w *Window) move(m msg.MessageMoveTerminal) {
+	//println("Window.move() - given x,y:", float32(m.X), float32(m.Y))
+	b := *w.Bounds
+	width := b.Width()
+	height := b.Height()
+	minimumVisibleSpan := float32(math.Min(float64(width), float64(height)))
+	minimumVisibleSpan /= 10
+
+	//FIXME?  positioning in desktop space by the size of Terminal's characters
+	//seems a bit wonky.  they could be any size for any given terminal, in theory.
+	//but i'm assuming the given coords should be similar
+	//to 80x25, so that it's similar to text mode positioning, in such a grid.
+	b.Left = -gl.CanvasExtents.X + float32(m.X)*w.charSize.X
+	b.Top = gl.CanvasExtents.Y - float32(m.Y)*w.charSize.Y
+	b.Top -= w.tabHeight()
+
+	//make sure at least a corner of the window remains visible in the desktop viewport
+	if b.Left < -gl.CanvasExtents.X-width+minimumVisibleSpan {
+		b.Left = -gl.CanvasExtents.X - width + minimumVisibleSpan
+	}
+
+	if b.Left > gl.CanvasExtents.X-minimumVisibleSpan {
+		b.Left = gl.CanvasExtents.X - minimumVisibleSpan
+	}
+
+	if b.Top > gl.CanvasExtents.Y+height-minimumVisibleSpan {
+		b.Top = gl.CanvasExtents.Y + height - minimumVisibleSpan
+		b.Top -= w.tabHeight()
+	}
+
+	if b.Top < -gl.CanvasExtents.Y+minimumVisibleSpan {
+		b.Top = -gl.CanvasExtents.Y + minimumVisibleSpan
+		b.Top -= w.tabHeight()
+	}
+
+	//set the bottom right corner, now that upper left has been clamped to valid space
+	b.Right = b.Left + width
+	b.Bottom = b.Top - height
+	w.MoveBy(app.Vec2F{b.Left - w.Bounds.Left, b.Top - w.Bounds.Top})
+}
+
+//how far an edge gets, in whole steps, toward 'to' (without passing it)
+func stepToward(edge, to, step float32) float32 {
+	return edge + float32(int((to-edge)/step))*step
+}
+
+func (p *Pane) terms(list []*Terminal) []*Terminal {
+	if p.Term != nil {
+		return append(list, p.Term)
+	}
+
+	return p.Halves[1].terms(p.Halves[0].terms(list))
+}
+
+//the leaf holding terminal id
+func (p *Pane) find(id msg.TerminalId) *Pane {
+	if p.Term != nil {
+		if p.Term.TerminalId == id {
+			return p
+		}
+
+		return nil
+	}
+
+	if found := p.Halves[0].find(id); found != nil {
+		return found
+	}
+
+	return p.Halves[1].find(id)
+}
+
+//takes the leaf of id out, its sibling taking the place of their parent.
+//(the root itself is never removed, so check for a lone pane 1st)
+func (p *Pane) remove(id msg.TerminalId) bool {
+	if p.Term != nil {
+		return false
+	}
+
+	for i, h := range p.Halves {
+		if h.Term != nil && h.Term.TerminalId == id {
+			*p = *p.Halves[1-i]
+			return true
+		}
+	}
+
+	return p.Halves[0].remove(id) || p.Halves[1].remove(id)
+}
+
+func (p *Pane) fitInto(r app.Rectangle, nominal app.Vec2F) {
+	if p.Term != nil {
+		p.Term.fitInto(r, nominal)
+		return
+	}
+
+	halves := splitEvenly(r, 2, p.SideBySide)
+	p.Halves[0].fitInto(halves[0], nominal)
+	p.Halves[1].fitInto(halves[1], nominal)
+}
+
+//resizes to cover the rectangle exactly.  the grid gets as many chars
+//of about nominal size as fit, & they're stretched to fill it
+func (t *Terminal) fitInto(r app.Rectangle, nominal app.Vec2F) {
+	b := t.BorderSize
+	w := r.Width() - b*2 - scrollBarWidth //grid space (pane minus its borders & scrollbar)
+	h := r.Height() - b*2
+
+	//(a hair of leeway, so float error doesn't lose a whole column/row)
+	cols := int(w/nominal.X + 0.001)
+	if cols < MinimumColumns {
+		cols = MinimumColumns
+	}
+
+	rows := int(h/nominal.Y + 0.001)
+	if rows < NumPromptLines+2 {
+		rows = NumPromptLines + 2
+	}
+
+	t.CharSize.X = w / float32(cols)
+	t.CharSize.Y = h / float32(rows)
+	*t.Bounds = r
+
+	if t.GridSize.X != cols || t.GridSize.Y != rows {
+		t.GridSize = app.Vec2I{cols, rows}
+		t.setupNewGrid()
+	}
+}
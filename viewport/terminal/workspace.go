@@ -0,0 +1,144 @@
+package terminal
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/skycoin/viscript/app"
+)
+
+//named sets of windows, only 1 of which is shown at a time.
+//(tasks of the hidden ones keep running, & their terminals keep up with them)
+type Workspace struct {
+	Name          string
+	TaskBarButton *app.Rectangle //(...bounds) in the switcher
+}
+
+const DefaultWorkspaceName = "main"
+
+//windows of the active workspace, in the order they were added
+func (ts *TerminalStack) Visible() []*Window {
+	windows := []*Window{}
+
+	for _, w := range ts.Windows {
+		if w.Workspace == ts.ActiveWorkspace {
+			windows = append(windows, w)
+		}
+	}
+
+	return windows
+}
+
+func (ts *TerminalStack) GetWorkspace(name string) *Workspace {
+	for _, ws := range ts.Workspaces {
+		if ws.Name == name {
+			return ws
+		}
+	}
+
+	return nil
+}
+
+//shows the workspace (making it, if there's none of that name yet), focusing its topmost window
+func (ts *TerminalStack) SwitchWorkspace(name string) error {
+	ws, err := ts.getOrAddWorkspace(name)
+	if err != nil {
+		return err
+	}
+
+	if ws != ts.ActiveWorkspace {
+		ts.showWorkspace(ws)
+		ts.focusTopmost()
+	}
+
+	return nil
+}
+
+//switches to the workspace 'step' places along
+func (ts *TerminalStack) NextWorkspace(step int) {
+	for i, ws := range ts.Workspaces {
+		if ws == ts.ActiveWorkspace {
+			next := ts.Workspaces[(i+step+len(ts.Workspaces))%len(ts.Workspaces)]
+			ts.SwitchWorkspace(next.Name)
+			return
+		}
+	}
+}
+
+func (ts *TerminalStack) MoveToWorkspace(w *Window, name string) error {
+	ws, err := ts.getOrAddWorkspace(name)
+	if err != nil {
+		return err
+	}
+
+	w.Workspace = ws
+
+	if w == ts.GetFocusedWindow() && ws != ts.ActiveWorkspace {
+		ts.focusTopmost()
+	}
+
+	ts.ApplyLayout()
+	ts.SetTaskBarButtonBounds()
+	return nil
+}
+
+//
+//
+//private
+//
+//
+
+func (ts *TerminalStack) getOrAddWorkspace(name string) (*Workspace, error) {
+	name = strings.TrimSpace(name)
+
+	if name == "" || strings.ContainsAny(name, " \t") {
+		return nil, errors.New("workspace names need to be a single word")
+	}
+
+	if ws := ts.GetWorkspace(name); ws != nil {
+		return ws, nil
+	}
+
+	ws := &Workspace{Name: name, TaskBarButton: &app.Rectangle{}}
+	ts.Workspaces = append(ts.Workspaces, ws)
+	return ws, nil
+}
+
+func (ts *TerminalStack) showWorkspace(ws *Workspace) {
+	prev := ts.ActiveWorkspace
+	ts.ActiveWorkspace = ws
+
+	//forget the one left behind, when it's empty (& not the last)
+	if prev != nil && len(ts.Workspaces) > 1 {
+		empty := true
+
+		for _, w := range ts.Windows {
+			if w.Workspace == prev {
+				empty = false
+			}
+		}
+
+		if empty {
+			for i, ows := range ts.Workspaces {
+				if ows == prev {
+					ts.Workspaces = append(ts.Workspaces[:i], ts.Workspaces[i+1:]...)
+					break
+				}
+			}
+		}
+	}
+
+	ts.ApplyLayout()
+	ts.SetTaskBarButtonBounds()
+}
+
+//focuses the window nearest the front of the active workspace (if any)
+func (ts *TerminalStack) focusTopmost() {
+	stacked := ts.Stacked()
+
+	if len(stacked) == 0 {
+		ts.Defocus()
+	} else {
+		ts.SetFocused(stacked[len(stacked)-1].Active().TerminalId)
+	}
+}
@@ -0,0 +1,99 @@
+package terminal
+
+import (
+	"sort"
+)
+
+//windows are drawn (& clicks are caught) by their place in a stack,
+//with always on top ones above the rest.  depths just follow it
+const maxDepth = 9.9 //of the topmost window (the taskbar & menus go above)
+
+//windows shown in the active workspace, bottom to top
+func (ts *TerminalStack) Stacked() []*Window {
+	windows := []*Window{}
+
+	for _, w := range ts.zOrder {
+		if w.Workspace == ts.ActiveWorkspace && !w.Minimized {
+			windows = append(windows, w)
+		}
+	}
+
+	return windows
+}
+
+func (ts *TerminalStack) Raise(w *Window) {
+	ts.removeFromStack(w)
+	ts.zOrder = append(ts.zOrder, w)
+	ts.restack()
+}
+
+//sends it to the bottom, focusing whatever is topmost then
+func (ts *TerminalStack) Lower(w *Window) {
+	ts.removeFromStack(w)
+	ts.zOrder = append([]*Window{w}, ts.zOrder...)
+	ts.restack()
+
+	if w == ts.GetFocusedWindow() {
+		ts.focusTopmost()
+	}
+}
+
+func (ts *TerminalStack) SetAlwaysOnTop(w *Window, onTop bool) {
+	w.AlwaysOnTop = onTop
+	ts.restack()
+}
+
+//moves its taskbar button (with those of its tabs) to where
+//the button of another window is, shifting the ones between
+func (ts *TerminalStack) MoveInTaskBar(w, to *Window) {
+	if w == to {
+		return
+	}
+
+	from, dest := -1, -1
+
+	for i, ow := range ts.Windows {
+		if ow == w {
+			from = i
+		}
+
+		if ow == to {
+			dest = i
+		}
+	}
+
+	if from < 0 || dest < 0 {
+		return
+	}
+
+	ts.Windows = append(ts.Windows[:from], ts.Windows[from+1:]...)
+	ts.Windows = append(ts.Windows[:dest], append([]*Window{w}, ts.Windows[dest:]...)...)
+	ts.ApplyLayout() //(tiles follow the same order)
+	ts.SetTaskBarButtonBounds()
+}
+
+//
+//
+//private
+//
+//
+
+func (ts *TerminalStack) removeFromStack(w *Window) {
+	for i, ow := range ts.zOrder {
+		if ow == w {
+			ts.zOrder = append(ts.zOrder[:i], ts.zOrder[i+1:]...)
+			return
+		}
+	}
+}
+
+//gives every window a depth by its place in the stack
+func (ts *TerminalStack) restack() {
+	sort.SliceStable(ts.zOrder, func(i, j int) bool {
+		return !ts.zOrder[i].AlwaysOnTop && ts.zOrder[j].AlwaysOnTop
+	})
+
+	for i, w := range ts.zOrder {
+		w.Depth = maxDepth * float32(i+1) / float32(len(ts.zOrder))
+	}
+}
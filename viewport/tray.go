@@ -0,0 +1,122 @@
+/*
+
+THE TRAY, AT THE RIGHT END OF THE TASKBAR, SHOWS THE TIME & HOW THINGS ARE
+GOING (APPS, SIGNAL CLIENTS, & VISCRIPT'S OWN MEMORY & GOROUTINES).  CLICKING
+AN ITEM RUNS THE COMMAND WHICH SHOWS ITS DETAILS, IN THE FOCUSED TERMINAL
+
+*/
+
+package viewport
+
+import (
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/skycoin/viscript/app"
+	"github.com/skycoin/viscript/hypervisor"
+	"github.com/skycoin/viscript/hypervisor/input/mouse"
+	"github.com/skycoin/viscript/hypervisor/procfs"
+	"github.com/skycoin/viscript/signal"
+	"github.com/skycoin/viscript/viewport/gl"
+	t "github.com/skycoin/viscript/viewport/terminal"
+)
+
+const trayRefreshInterval = time.Second //(reading memory stats stops the world briefly)
+
+var (
+	tray          []*TrayItem //left to right
+	trayUpdatedAt time.Time
+)
+
+type TrayItem struct {
+	MenuOption
+	Command string //which shows its details
+	Alert   bool   //always drawn lit up
+}
+
+func drawTray() {
+	if time.Since(trayUpdatedAt) >= trayRefreshInterval {
+		updateTray()
+	}
+
+	setTrayBounds()
+
+	for _, item := range tray {
+		drawTaskBarTerminalButton(item.Bounds, item.Name, item.Highlighted || item.Alert, false)
+	}
+}
+
+func highlightTrayItems() {
+	for _, item := range tray {
+		item.Highlighted = item.Bounds != nil && mouse.PointerIsInside(item.Bounds)
+	}
+}
+
+//returns whether an item was clicked
+func onTrayClick() bool {
+	for _, item := range tray {
+		if item.Bounds != nil && mouse.PointerIsInside(item.Bounds) {
+			runInFocusedTerminal(item.Command)
+			return true
+		}
+	}
+
+	return false
+}
+
+//
+//
+//private
+//
+//
+
+func updateTray() {
+	trayUpdatedAt = time.Now()
+
+	var mem runtime.MemStats
+	runtime.ReadMemStats(&mem)
+	running, crashed := hypervisor.CountExternalApps()
+
+	tray = []*TrayItem{}
+	addTrayItem(fmt.Sprintf("Terms %d", len(t.Terms.TermMap)), "list_terms")
+	addTrayItem(fmt.Sprintf("Apps %d", running), "list_apps")
+
+	if crashed > 0 {
+		addTrayItem(fmt.Sprintf("Crashed %d", crashed), "list_apps").Alert = true
+	}
+
+	addTrayItem(fmt.Sprintf("Clients %d", len(signal.ClientIds())), "list_clients")
+	addTrayItem("Mem "+procfs.FormatBytes(mem.Sys), "sys_info")
+	addTrayItem(fmt.Sprintf("Go %d", runtime.NumGoroutine()), "sys_info")
+	addTrayItem(trayUpdatedAt.Format("15:04"), "sys_info")
+	highlightTrayItems()
+}
+
+func addTrayItem(text, command string) *TrayItem {
+	item := &TrayItem{MenuOption: MenuOption{Name: text}, Command: command}
+	tray = append(tray, item)
+	return item
+}
+
+//packed against the right end, leaving the rest of the taskbar for the buttons
+func setTrayBounds() {
+	right := gl.CanvasExtents.X - app.TaskBarBorderSpan
+
+	for i := len(tray) - 1; i >= 0; i-- {
+		width := app.TaskBarCharWid*float32(len(tray[i].Name)) + app.TaskBarBorderSpan*2
+
+		tray[i].Bounds = &app.Rectangle{
+			-gl.CanvasExtents.Y - app.TaskBarBorderSpan + app.TaskBarHeight,
+			right,
+			-gl.CanvasExtents.Y + app.TaskBarBorderSpan,
+			right - width}
+
+		right -= width
+	}
+
+	if width := gl.CanvasExtents.X - app.TaskBarBorderSpan - right; width != t.Terms.TrayWidth {
+		t.Terms.TrayWidth = width
+		t.Terms.SetTaskBarButtonBounds()
+	}
+}
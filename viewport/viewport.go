@@ -59,6 +59,11 @@ func DispatchEvents() []byte {
 }
 
 func Tick() {
+	if igl.WindowShouldClose() {
+		CloseWindow = true
+	}
+
+	igl.ApplyThemeIfChanged()
 	igl.Curs.Tick()
 	term.Terms.Tick()
 }
@@ -67,6 +72,9 @@ func UpdateDrawBuffer() {
 	igl.DrawBegin()
 	term.Terms.Draw()
 	drawTaskBarStartButtonAndMenu()
+	drawContextMenu()
+	drawClosePrompt()
+	drawParamPrompt()
 	igl.DrawEnd()
 }
 
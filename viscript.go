@@ -51,38 +51,72 @@
 package main
 
 import (
+	"flag"
+	"fmt"
 	"os"
+	ossignal "os/signal"
+	"syscall"
+
+	"github.com/sirupsen/logrus"
 
 	"github.com/skycoin/viscript/app"
 	"github.com/skycoin/viscript/config"
 	"github.com/skycoin/viscript/headless"
 	"github.com/skycoin/viscript/hypervisor"
+	"github.com/skycoin/viscript/hypervisor/stack"
+	termTask "github.com/skycoin/viscript/hypervisor/task/terminal"
 	"github.com/skycoin/viscript/reds_rpc"
 	"github.com/skycoin/viscript/signal"
 	"github.com/skycoin/viscript/viewport"
 )
 
+var (
+	rpcInstance = reds_rpc.NewRPC()
+	quitSignal  = make(chan struct{}, 1) //from handleOsSignals, polled by the main loop
+)
+
+//command line flags (each one, except -config, can also be set
+//in the config file & by environment variable, see config/settings.go)
+var (
+	configPath      = flag.String("config", "", "config file (default: searched for, see below)")
+	checkOnly       = flag.Bool("check-config", false, "check the config file, print its problems & exit (non-zero if any)")
+	headlessFlag    = flag.Bool("headless", false, "run without terminals and OpenGL front")
+	oldHeadlessFlag = flag.Bool("run_headless", false, "same as -headless (deprecated)")
+	hHeadlessFlag   = flag.Bool("h", false, "same as -headless (deprecated, use -help for this)")
+	rpcAddr         = flag.String("rpc-addr", "", "address the RPC server listens on (default \""+config.DefaultRPCAddr+"\")")
+	signalAddr      = flag.String("signal-addr", "", "address the signal server listens on (default \""+config.DefaultSignalAddr+"\")")
+	logLevelName    = flag.String("log-level", "", "panic, fatal, error, warn, info or debug (default \""+config.DefaultLogLevel+"\")")
+)
+
 func main() {
+	flag.Usage = printUsage
+	flag.Parse()
+
+	if *checkOnly {
+		os.Exit(checkConfig())
+	}
+
 	app.MakeHighlyVisibleLogEntry(app.Name, 13)
 	loadConfig()
 	handleAnyArguments()
 	inits()
+	handleOsSignals()
 
-	go func() {
-		rpcInstance := reds_rpc.NewRPC()
-		rpcInstance.Serve()
-	}()
+	go rpcInstance.Serve(config.RPCAddr())
 
-	err := signal.Listen("0.0.0.0:7999")
+	err := signal.Listen(config.SignalAddr())
 	if err != nil {
 		panic(err)
 	}
 
 	//start looping
 	for viewport.CloseWindow == false {
+		pollOsSignals()
 		viewport.DispatchEvents() //event channel
 		hypervisor.TickTasks()
 		hypervisor.TickExternalApps()
+		stack.Tick() //(those being started)
+		termTask.TickConfigWatcher()
 
 		if config.Global.Settings.RunHeadless {
 			headless.Tick()
@@ -94,25 +128,144 @@ func main() {
 		}
 	}
 
-	viewport.TeardownScreen()
-	hypervisor.Teardown()
+	if !config.Global.Settings.RunHeadless {
+		viewport.TeardownScreen()
+	}
+
+	shutdown()
+}
+
+func shutdown() {
+	app.MakeHighlyVisibleLogEntry("SHUTTING DOWN", 5)
+	hypervisor.Teardown() //apps (newest first), dbus channels, logs
+	rpcInstance.Close()
+	signal.Close()
+}
+
+//SIGINT/SIGTERM leave the main loop, so everything gets shut down in order
+func handleOsSignals() {
+	sigs := make(chan os.Signal, 2)
+	ossignal.Notify(sigs, os.Interrupt, syscall.SIGTERM)
+
+	go func() {
+		<-sigs
+		println("\n\nRECEIVED SIGNAL TO QUIT")
+		quitSignal <- struct{}{}
+
+		<-sigs //impatient user, don't wait for apps to stop
+		os.Exit(1)
+	}()
+}
+
+//(so only the main loop touches CloseWindow)
+func pollOsSignals() {
+	select {
+	case <-quitSignal:
+		viewport.CloseWindow = true
+	default:
+	}
 }
 
+//an invalid config file is fatal, but no config file at all isn't
 func loadConfig() {
-	err := config.Load("config.yaml")
+	path, err := configFilePath()
 	if err != nil {
 		println(err.Error())
 		return
 	}
+
+	err = config.Load(path)
+	if err != nil {
+		println("Invalid config file " + path + ":\n" + err.Error())
+		os.Exit(1)
+	}
+}
+
+//for -check-config.  warnings count too, as something won't work
+func checkConfig() int {
+	path, err := configFilePath()
+	if err != nil {
+		println(err.Error())
+		return 1
+	}
+
+	_, problems, err := config.Parse(path)
+	if err != nil {
+		println(path + ": " + err.Error())
+		return 1
+	}
+
+	for _, p := range problems {
+		println(path + ": " + p.String())
+	}
+
+	if len(problems) > 0 {
+		return 1
+	}
+
+	println(path + ": OK")
+	return 0
 }
 
+func configFilePath() (string, error) {
+	if *configPath != "" {
+		return *configPath, nil
+	}
+
+	return config.FindFile()
+}
+
+//flags win over everything else, so they're applied after loading
 func handleAnyArguments() {
-	args := os.Args[1:]
-	if len(args) == 1 {
-		if args[0] == "-h" || args[0] == "-run_headless" {
-			config.Global.Settings.RunHeadless = true //override defalt
-			app.MakeHighlyVisibleLogEntry("Running in HEADLESS MODE", 9)
+	o := config.Overrides{
+		RPCAddr:    *rpcAddr,
+		SignalAddr: *signalAddr,
+		LogLevel:   *logLevelName,
+	}
+
+	//only when given, so "runHeadless: true" in the file isn't undone
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "headless":
+			o.Headless = headlessFlag
+		case "run_headless":
+			o.Headless = oldHeadlessFlag
+		case "h":
+			o.Headless = hHeadlessFlag
 		}
+	})
+
+	config.SetOverrides(o)
+
+	level, err := logrus.ParseLevel(config.LogLevel())
+	if err != nil {
+		println(err.Error())
+		os.Exit(2)
+	}
+
+	logrus.SetLevel(level)
+	app.TraceCalls = level >= logrus.DebugLevel
+
+	if config.Global.Settings.RunHeadless {
+		app.MakeHighlyVisibleLogEntry("Running in HEADLESS MODE", 9)
+	}
+}
+
+func printUsage() {
+	fmt.Fprintf(os.Stderr, "Usage: %s [flags]\n\nFlags:\n", os.Args[0])
+	flag.PrintDefaults()
+
+	fmt.Fprintf(os.Stderr, "\nEnvironment (overridden by flags, overrides config file):\n")
+	fmt.Fprintf(os.Stderr, "  %s, %s, %s, %s, %s\n", config.EnvConfig, config.EnvHeadless,
+		config.EnvRPCAddr, config.EnvSignalAddr, config.EnvLogLevel)
+
+	fmt.Fprintf(os.Stderr, "\nWithout -config, the first of these is used:\n")
+	if path := os.Getenv(config.EnvConfig); path != "" {
+		fmt.Fprintf(os.Stderr, "  %s (from %s)\n", path, config.EnvConfig)
+	}
+
+	for _, path := range config.SearchPaths() {
+		fmt.Fprintf(os.Stderr, "  %s\n", path)
 	}
 }
 